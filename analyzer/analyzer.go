@@ -1,104 +1,322 @@
 package analyzer
 
 import (
-	"bufio"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+
+	"github.com/byterocket/c4udit/analyzer/solidity"
 )
 
+// Options configures an analysis run.
+type Options struct {
+	// Jobs is the number of files analyzed in parallel. Values below 1
+	// default to the number of CPUs.
+	Jobs int
+	// Root is the project root. Paths in the report are relative to it.
+	// Defaults to the common root of the analyzed paths.
+	Root string
+	// Include and Exclude select the files found in directories, see
+	// collectFiles. Files given as paths are always analyzed.
+	Include []string
+	Exclude []string
+	// NoDefaultExcludes disables DefaultExcludes.
+	NoDefaultExcludes bool
+	// NoGitignore disables the exclusion of files ignored by .gitignore
+	// files.
+	NoGitignore bool
+	// Scope restricts the analysis to the listed files, see LoadScope.
+	// Include, Exclude and the default excludes do not apply to them.
+	Scope []string
+}
+
 // Run an analysis of Solidity contracts in `path`.
-// Argument `issues` encodes the Issues to search for.
-func Run(issues []Issue, paths []string) (*Report, error) {
-	report := &Report{
-		Issues:           issues,
-		FilesAnalyzed:    []string{},
-		FindingsPerIssue: make(map[string][]Finding),
+// Argument `issues` encodes the Issues to search for. The issues are
+// validated and compiled before any file is analyzed.
+// Files are analyzed in parallel, the report does not depend on the order in
+// which they finish.
+// Paths and files that can not be read do not stop the analysis, they are
+// listed in the report's Errors instead.
+func Run(issues []Issue, paths []string, opts Options) (*Report, error) {
+	ruleset, err := Compile(issues)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, path := range paths {
-		err := run(report, path)
-		if err != nil {
-			return &Report{}, nil
+	report, err := newReport(issues, paths, opts)
+	if err != nil {
+		return nil, err
+	}
+	files := collectFiles(report, paths, opts)
+
+	jobs := opts.Jobs
+	if jobs < 1 {
+		jobs = runtime.NumCPU()
+	}
+
+	// Analyze files in parallel, each result is stored at its file's index.
+	results := make([]*fileResult, len(files))
+	errs := make([]error, len(files))
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i], errs[i] = analyzeFile(ruleset, files[i], report.relativePath(files[i]))
+			}
+		}()
+	}
+	for i := range files {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, file := range files {
+		if errs[i] != nil {
+			report.Errors = append(report.Errors, report.newAnalysisError(file, errs[i]))
+			continue
+		}
+
+		// Add file and findings to report.
+		report.FilesAnalyzed = append(report.FilesAnalyzed, report.relativePath(file))
+		for _, issue := range report.Issues {
+			report.FindingsPerIssue[issue.Identifier] = append(report.FindingsPerIssue[issue.Identifier],
+				results[i].findings[issue.Identifier]...,
+			)
+		}
+
+		// Add suppressions to report.
+		for _, s := range results[i].suppressions {
+			report.Suppressions = append(report.Suppressions, *s)
 		}
+		for id, count := range results[i].suppressed {
+			report.SuppressedPerIssue[id] += count
+		}
+	}
+
+	for _, findings := range report.FindingsPerIssue {
+		sortFindings(findings)
 	}
 
 	return report, nil
 }
 
-func run(report *Report, path string) error {
-	pathInfo, err := os.Stat(path)
+// ListFiles returns the files Run analyzes for `paths` and `opts`, relative
+// to the root, and the paths that can not be read.
+func ListFiles(paths []string, opts Options) ([]string, []AnalysisError, error) {
+	report, err := newReport(nil, paths, opts)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	if pathInfo.IsDir() {
-		files, err := ioutil.ReadDir(path)
-		if err != nil {
-			return err
-		}
+	files := []string{}
+	for _, file := range collectFiles(report, paths, opts) {
+		files = append(files, report.relativePath(file))
+	}
+	return files, report.Errors, nil
+}
 
-		for _, file := range files {
-			err = run(report, filepath.Join(path, file.Name()))
-			if err != nil {
-				return err
-			}
-		}
-	} else {
-		file := path
+// ReportRoot returns the absolute root of the report of an analysis of
+// `paths`: opts.Root, or the deepest directory containing all paths.
+func ReportRoot(paths []string, opts Options) (string, error) {
+	root := opts.Root
+	if root == "" {
+		root = commonRoot(paths)
+	}
+	return filepath.Abs(root)
+}
 
-		// Only analyze Solidity files
-		if !strings.HasSuffix(file, ".sol") {
-			return nil
-		}
+// newReport returns an empty Report for an analysis of `paths`.
+func newReport(issues []Issue, paths []string, opts Options) (*Report, error) {
+	root, err := ReportRoot(paths, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Report{
+		Root:               root,
+		ProjectRoot:        projectRoot(root),
+		Issues:             issues,
+		FilesAnalyzed:      []string{},
+		FindingsPerIssue:   make(map[string][]Finding),
+		Errors:             []AnalysisError{},
+		Suppressions:       []Suppression{},
+		SuppressedPerIssue: make(map[string]int),
+	}, nil
+}
+
+// newAnalysisError returns an AnalysisError for `err` which occurred while
+// reading `path`.
+func (r *Report) newAnalysisError(path string, err error) AnalysisError {
+	if pathErr, ok := err.(*os.PathError); ok {
+		err = pathErr.Err
+	}
+	return AnalysisError{
+		Path:    r.relativePath(path),
+		Message: err.Error(),
+	}
+}
 
-		findingsPerIssue, err := analyzeFile(report.Issues, file)
+// relativePath returns `path` relative to the report's root, using forward
+// slashes. Paths outside of the root are returned as given.
+func (r *Report) relativePath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(r.Root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// commonRoot returns the deepest directory containing all `paths`.
+func commonRoot(paths []string) string {
+	root := ""
+	for _, path := range paths {
+		dir, err := filepath.Abs(path)
 		if err != nil {
-			return err
+			continue
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			dir = filepath.Dir(dir)
 		}
 
-		// Add file and findings to report.
-		report.FilesAnalyzed = append(report.FilesAnalyzed, file)
-		for _, issue := range report.Issues {
-			report.FindingsPerIssue[issue.Identifier] = append(report.FindingsPerIssue[issue.Identifier],
-				findingsPerIssue[issue.Identifier]...,
-			)
+		if root == "" {
+			root = dir
+			continue
+		}
+		for root != filepath.Dir(root) && dir != root && !strings.HasPrefix(dir, root+string(filepath.Separator)) {
+			root = filepath.Dir(root)
 		}
 	}
 
-	return nil
+	if root == "" {
+		return "."
+	}
+	return root
 }
 
-func analyzeFile(issues []Issue, file string) (map[string][]Finding, error) {
-	findings := make(map[string][]Finding)
+// sortFindings sorts findings by file and position.
+func sortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.LineNumber != b.LineNumber {
+			return a.LineNumber < b.LineNumber
+		}
+		return a.Column < b.Column
+	})
+}
 
-	readFile, err := os.Open(file)
+// fileResult is the result of analyzing a single file.
+type fileResult struct {
+	findings     map[string][]Finding
+	suppressions []*Suppression
+	// Number of suppressed findings per Issue Identifier.
+	suppressed map[string]int
+}
+
+// analyzeFile analyzes the file at `path`. Findings refer to the file by
+// `name`.
+func analyzeFile(ruleset *Ruleset, path string, name string) (*fileResult, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := NewSourceFile(name, string(content))
 	if err != nil {
 		return nil, err
 	}
-	defer readFile.Close()
-
-	scanner := bufio.NewScanner(readFile)
-	lineNumber := 0
-	for scanner.Scan() {
-		lineNumber++
-		line := scanner.Text()
-
-		for _, issue := range issues {
-			matched, _ := regexp.MatchString(issue.Pattern, line)
-			if matched {
-				// fmt.Println(">>>", strings.Split(file, "/")[len(strings.Split(file, "/"))-1])
-				findings[issue.Identifier] = append(findings[issue.Identifier], Finding{
-					IssueIdentifier: issue.Identifier,
-					File:            strings.Split(file, "/")[len(strings.Split(file, "/"))-1],
-					LineNumber:      lineNumber,
-					LineContent:     strings.TrimSpace(line),
-				})
+
+	findings := make(map[string][]Finding)
+	suppressed := make(map[string]int)
+	suppressions, regions := parseSuppressions(file)
+
+	for i, issue := range ruleset.Issues {
+		if !issue.appliesTo(name) {
+			continue
+		}
+
+		var found []Finding
+		if issue.Detector != nil {
+			found = issue.Detector.Detect(file)
+		} else {
+			found = matchPattern(issue, ruleset.patterns[i], file)
+		}
+
+	findings:
+		for _, finding := range found {
+			finding.IssueIdentifier = issue.Identifier
+			if ruleset.excluded(i, finding) {
+				continue
+			}
+
+			for _, r := range regions {
+				if r.suppresses(finding) {
+					r.suppression.Count++
+					suppressed[issue.Identifier]++
+					continue findings
+				}
 			}
+
+			findings[issue.Identifier] = append(findings[issue.Identifier], finding)
+		}
+	}
+
+	return &fileResult{
+		findings:     findings,
+		suppressions: suppressions,
+		suppressed:   suppressed,
+	}, nil
+}
+
+// matchPattern returns the findings of an Issue's compiled pattern in `file`.
+func matchPattern(issue Issue, pattern *regexp.Regexp, file *SourceFile) []Finding {
+	// Code is matched per logical statement, so that statements split over
+	// several lines are matched as a whole. Comments are matched per line.
+	var view string
+	var units []statement
+	switch issue.Scope {
+	case COMMENTS:
+		view = file.Comments()
+		offset := 0
+		for _, line := range file.lines {
+			units = append(units, statement{offset, offset + len(line)})
+			offset += len(line) + 1
 		}
+	case STRINGS:
+		view = file.lexed.view(solidity.CODE, solidity.LITERAL)
+		units = file.statements
+	default:
+		view = file.Code()
+		units = file.statements
+	}
+
+	findings := []Finding{}
+	for _, unit := range units {
+		loc := pattern.FindStringSubmatchIndex(flatten(view[unit.start:unit.end]))
+		if loc == nil {
+			continue
+		}
+		start, end := unit.start+loc[0], unit.start+loc[1]
+		if issue.Scope == STRINGS && !file.lexed.overlaps(start, end, solidity.LITERAL) {
+			continue
+		}
+
+		findings = append(findings, file.Finding(start, end))
 	}
 
-	return findings, nil
+	return findings
 }
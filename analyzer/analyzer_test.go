@@ -0,0 +1,181 @@
+package analyzer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// fixtureReport returns the report of all issues for a directory holding
+// detectorSource as A.sol, which is the report's root. The `extra` paths
+// in the directory, e.g. missing files, are analyzed too.
+func fixtureReport(t *testing.T, extra ...string) *Report {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "A.sol"), []byte(detectorSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+	paths := []string{dir}
+	for _, path := range extra {
+		paths = append(paths, filepath.Join(dir, path))
+	}
+	report, err := Run(AllIssues(), paths, Options{Root: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+// sourceFile returns the SourceFile of `content`, failing the test if it
+// can not be parsed.
+func sourceFile(t *testing.T, content string) *SourceFile {
+	file, err := NewSourceFile("C.sol", content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestRunDeterministic(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 20; i++ {
+		err := ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("C%02d.sol", i)), []byte(detectorSource), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := Run(AllIssues(), []string{dir}, Options{Jobs: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(want.FilesAnalyzed) != 20 {
+		t.Fatalf("analyzed %d files, want 20", len(want.FilesAnalyzed))
+	}
+
+	for i := 0; i < 5; i++ {
+		got, err := Run(AllIssues(), []string{dir}, Options{Jobs: 8})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got.FilesAnalyzed, want.FilesAnalyzed) || got.String() != want.String() {
+			t.Fatalf("parallel run differs from sequential run")
+		}
+	}
+}
+
+func TestRunReportsErrors(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "C.sol")
+	if err := ioutil.WriteFile(file, []byte(detectorSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "Missing.sol")
+
+	report, err := Run(AllIssues(), []string{missing, file}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(report.FilesAnalyzed, []string{"C.sol"}) {
+		t.Errorf("got files analyzed %v, want %v", report.FilesAnalyzed, []string{"C.sol"})
+	}
+	want := []AnalysisError{{Path: "Missing.sol", Message: "no such file or directory"}}
+	if !reflect.DeepEqual(report.Errors, want) {
+		t.Errorf("got errors %v, want %v", report.Errors, want)
+	}
+	if len(report.FindingsPerIssue["L-01"]) == 0 {
+		t.Errorf("readable file was not analyzed")
+	}
+}
+
+func TestRunRelativePaths(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"src/v1/Token.sol", "src/v2/Token.sol"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(detectorSource), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	v1, v2 := filepath.Join(dir, "src", "v1"), filepath.Join(dir, "src", "v2")
+
+	tests := []struct {
+		root string
+		want []string
+	}{
+		{"", []string{"v1/Token.sol", "v2/Token.sol"}},
+		{dir, []string{"src/v1/Token.sol", "src/v2/Token.sol"}},
+	}
+
+	for _, test := range tests {
+		report, err := Run(AllIssues(), []string{v1, v2}, Options{Root: test.root})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(report.FilesAnalyzed, test.want) {
+			t.Errorf("root %q: got files analyzed %v, want %v", test.root, report.FilesAnalyzed, test.want)
+		}
+		files := []string{}
+		for _, f := range report.FindingsPerIssue["L-01"] {
+			if len(files) == 0 || files[len(files)-1] != f.File {
+				files = append(files, f.File)
+			}
+		}
+		if !reflect.DeepEqual(files, test.want) {
+			t.Errorf("root %q: got findings in %v, want %v", test.root, files, test.want)
+		}
+	}
+}
+
+func TestFindingColumns(t *testing.T) {
+	file := sourceFile(t, "contract C {\n    uint x = y / 2;\n}\n")
+	issue := Issue{Identifier: "G-07", Pattern: `/ [2,4,8]`}
+	ruleset, err := Compile([]Issue{issue})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings := matchPattern(issue, ruleset.patterns[0], file)
+	if len(findings) != 1 {
+		t.Fatalf("got %d findings, want 1", len(findings))
+	}
+
+	f := findings[0]
+	if f.LineNumber != 2 || f.Column != 16 || f.EndColumn != 19 || f.Match != "/ 2" {
+		t.Errorf("got finding %+v", f)
+	}
+
+	want := "C.sol::2 => uint x = y / 2;\n" +
+		"                       ^^^\n"
+	if got := f.render(true); got != want {
+		t.Errorf("got rendering\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRunTruncatedFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "A.sol"), []byte(detectorSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "B.sol"), []byte("abstract"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Run(AllIssues(), []string{dir}, Options{Root: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(report.FilesAnalyzed, []string{"A.sol"}) {
+		t.Errorf("got files %v, want only A.sol", report.FilesAnalyzed)
+	}
+	want := []AnalysisError{{Path: "B.sol", Message: "line 1: unexpected end of file"}}
+	if !reflect.DeepEqual(report.Errors, want) {
+		t.Errorf("got errors %v, want %v", report.Errors, want)
+	}
+}
@@ -0,0 +1,173 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// BaselineVersion is the version of the baseline file format.
+const BaselineVersion = 2
+
+// Baseline is a recorded set of findings. Findings in a baseline are
+// hidden from later reports, so that only new findings are shown.
+//
+// Findings are identified by a fingerprint of their issue, file, enclosing
+// function and whitespace-normalized line content, so that they still match
+// after lines moved. See Report.Fingerprint.
+type Baseline struct {
+	Version  int             `json:"version"`
+	Findings []BaselineEntry `json:"findings"`
+}
+
+// BaselineEntry is a finding recorded in a Baseline.
+type BaselineEntry struct {
+	Fingerprint string `json:"fingerprint"`
+	Issue       string `json:"issue"`
+	File        string `json:"file"`
+	Function    string `json:"function,omitempty"`
+	Content     string `json:"content"`
+}
+
+// BaselineStats summarizes the comparison of a Report with a Baseline.
+type BaselineStats struct {
+	// Known is the number of findings hidden because they are in the
+	// baseline.
+	Known int
+	// Stale is the number of baseline entries no longer found.
+	Stale int
+}
+
+// NewBaseline returns a Baseline of all findings in report `r`.
+func NewBaseline(r *Report) *Baseline {
+	baseline := &Baseline{
+		Version:  BaselineVersion,
+		Findings: []BaselineEntry{},
+	}
+
+	for _, issue := range r.Issues {
+		for _, f := range r.FindingsPerIssue[issue.Identifier] {
+			baseline.Findings = append(baseline.Findings, BaselineEntry{
+				Fingerprint: r.Fingerprint(f),
+				Issue:       f.IssueIdentifier,
+				File:        r.projectPath(f.File),
+				Function:    f.Function,
+				Content:     normalizeContent(f.LineContent),
+			})
+		}
+	}
+
+	return baseline
+}
+
+// LoadBaseline reads a Baseline from the file at `path`.
+func LoadBaseline(path string) (*Baseline, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	baseline := &Baseline{}
+	if err := json.Unmarshal(content, baseline); err != nil {
+		return nil, fmt.Errorf("invalid baseline %s: %s", path, err)
+	}
+	if baseline.Version != BaselineVersion {
+		return nil, fmt.Errorf("unsupported baseline version %d in %s, record it again with -baseline-write", baseline.Version, path)
+	}
+
+	return baseline, nil
+}
+
+// Save writes the baseline as JSON to the file at `path`.
+func (b *Baseline) Save(path string) error {
+	content, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(content, '\n'), 0644)
+}
+
+// ApplyBaseline removes the findings in baseline `b` from report `r` and
+// records the comparison in r.Baseline. Each baseline entry hides at most
+// one finding. Entries of issues not in the report, e.g. not selected with
+// -only, are ignored.
+func (r *Report) ApplyBaseline(b *Baseline) {
+	selected := make(map[string]bool)
+	for _, issue := range r.Issues {
+		selected[issue.Identifier] = true
+	}
+	known := make(map[string]int)
+	for _, e := range b.Findings {
+		if selected[e.Issue] {
+			known[e.Fingerprint]++
+		}
+	}
+
+	stats := &BaselineStats{}
+	for id, findings := range r.FindingsPerIssue {
+		remaining := []Finding{}
+		for _, f := range findings {
+			fingerprint := r.Fingerprint(f)
+			if known[fingerprint] > 0 {
+				known[fingerprint]--
+				stats.Known++
+				continue
+			}
+			remaining = append(remaining, f)
+		}
+		r.FindingsPerIssue[id] = remaining
+	}
+
+	for _, count := range known {
+		stats.Stale += count
+	}
+	r.Baseline = stats
+}
+
+// Fingerprint returns an identifier of finding `f` that does not depend on
+// its line number. Its file is taken relative to the project root, so that
+// the fingerprint does not depend on the paths given for the analysis.
+func (r Report) Fingerprint(f Finding) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		f.IssueIdentifier,
+		r.projectPath(f.File),
+		f.Function,
+		normalizeContent(f.LineContent),
+	}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// projectRoot returns the git working tree containing `root`, or `root`
+// outside of git repositories.
+func projectRoot(root string) string {
+	if tree, _, err := findGitDir(root); err == nil {
+		return tree
+	}
+	return root
+}
+
+// projectPath returns `file`, relative to the report's root, relative to
+// the project root instead. Files outside of it are returned as given.
+func (r Report) projectPath(file string) string {
+	if r.ProjectRoot == "" {
+		return file
+	}
+	path := filepath.FromSlash(file)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.Root, path)
+	}
+	rel, err := filepath.Rel(r.ProjectRoot, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return file
+	}
+	return filepath.ToSlash(rel)
+}
+
+// normalizeContent collapses all whitespace in `s` to single spaces.
+func normalizeContent(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
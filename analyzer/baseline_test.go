@@ -0,0 +1,115 @@
+package analyzer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBaseline(t *testing.T) {
+	finding := func(line int, content string) Finding {
+		return Finding{
+			IssueIdentifier: "G-06",
+			File:            "src/A.sol",
+			Function:        "A.f",
+			LineNumber:      line,
+			LineContent:     content,
+		}
+	}
+
+	old := &Report{
+		Issues: []Issue{{Identifier: "G-06"}},
+		FindingsPerIssue: map[string][]Finding{
+			"G-06": {finding(10, "i++;"), finding(12, "i++;"), finding(20, "j++;")},
+		},
+	}
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := NewBaseline(old).Save(path); err != nil {
+		t.Fatal(err)
+	}
+	baseline, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Lines moved, one `i++` and `j++` were fixed, `k++` is new.
+	current := &Report{
+		Issues: []Issue{{Identifier: "G-06"}},
+		FindingsPerIssue: map[string][]Finding{
+			"G-06": {finding(14, "i++;"), finding(30, "k++;")},
+		},
+	}
+	current.ApplyBaseline(baseline)
+
+	findings := current.FindingsPerIssue["G-06"]
+	if len(findings) != 1 || findings[0].LineContent != "k++;" {
+		t.Errorf("got findings %+v, want only the new one", findings)
+	}
+	if *current.Baseline != (BaselineStats{Known: 1, Stale: 2}) {
+		t.Errorf("got baseline stats %+v, want 1 known and 2 stale", *current.Baseline)
+	}
+}
+
+func TestBaselineIgnoresUnselectedIssues(t *testing.T) {
+	old := &Report{
+		Issues: []Issue{{Identifier: "G-06"}, {Identifier: "L-02"}},
+		FindingsPerIssue: map[string][]Finding{
+			"G-06": {{IssueIdentifier: "G-06", File: "A.sol", LineContent: "i++;"}},
+			"L-02": {{IssueIdentifier: "L-02", File: "A.sol", LineContent: "pragma solidity ^0.8.0;"}},
+		},
+	}
+
+	// Only G-06 is selected, e.g. with -only G-06.
+	current := &Report{
+		Issues: []Issue{{Identifier: "G-06"}},
+		FindingsPerIssue: map[string][]Finding{
+			"G-06": {{IssueIdentifier: "G-06", File: "A.sol", LineContent: "i++;"}},
+		},
+	}
+	current.ApplyBaseline(NewBaseline(old))
+	if *current.Baseline != (BaselineStats{Known: 1, Stale: 0}) {
+		t.Errorf("got baseline stats %+v, want 1 known and none stale", *current.Baseline)
+	}
+}
+
+func TestFingerprintIndependentOfPaths(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{".git", "src", "test"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	src := filepath.Join(dir, "src")
+	if err := ioutil.WriteFile(filepath.Join(src, "A.sol"), []byte("pragma solidity ^0.8.0;\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The report root is src in the first run and the repository in the
+	// second one.
+	fingerprints := func(paths ...string) []string {
+		report, err := Run(AllIssues(), paths, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries := NewBaseline(report).Findings
+		if len(entries) == 0 {
+			t.Fatalf("%v: no findings", paths)
+		}
+		got := []string{}
+		for _, e := range entries {
+			got = append(got, e.Fingerprint+" "+e.File)
+		}
+		return got
+	}
+	first := fingerprints(src)
+	second := fingerprints(src, filepath.Join(dir, "test"))
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("fingerprints changed with the paths:\n%v\n%v", first, second)
+	}
+	if !strings.HasSuffix(first[0], " src/A.sol") {
+		t.Errorf("got baseline entry %q, want file relative to the repository", first[0])
+	}
+}
@@ -0,0 +1,180 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// c4Sections are the sections of the Code4rena reports, in order.
+var c4Sections = []struct {
+	Severity Severity
+	Prefix   string
+	Heading  string
+}{
+	{LOW, "L", "Low Risk Issues"},
+	{NC, "N", "Non-Critical Issues"},
+	{GASOP, "G", "Gas Optimizations"},
+}
+
+// QAMarkdown returns the Code4rena QA report: the low and non-critical
+// findings, see c4Markdown.
+func (r Report) QAMarkdown(opts RenderOptions) string {
+	return r.c4Markdown("QA Report", []Severity{LOW, NC}, opts)
+}
+
+// GasMarkdown returns the Code4rena gas report: the gas optimization
+// findings, see c4Markdown.
+func (r Report) GasMarkdown(opts RenderOptions) string {
+	return r.c4Markdown("Gas Report", []Severity{GASOP}, opts)
+}
+
+// c4Markdown returns a Code4rena report of the findings of `severities` in
+// the layout used by wardens. The issues are numbered per severity, e.g.
+// L-01, L-02 and N-01, in the order of the report. A summary table of each
+// severity links to the issues and counts their instances.
+func (r Report) c4Markdown(title string, severities []Severity, opts RenderOptions) string {
+	type numberedIssue struct {
+		Issue
+		Number   string
+		Anchor   string
+		Findings []Finding
+	}
+
+	// Number the issues. Their headings are the only ones that could
+	// repeat, so their anchors are independent of the other headings.
+	anchors := newAnchors()
+	sections := make(map[Severity][]numberedIssue)
+	for _, section := range c4Sections {
+		if !containsSeverity(severities, section.Severity) {
+			continue
+		}
+		for _, issue := range r.Issues {
+			findings := r.FindingsPerIssue[issue.Identifier]
+			if issue.Severity != section.Severity || len(findings) == 0 {
+				continue
+			}
+			number := fmt.Sprintf("%s-%02d", section.Prefix, len(sections[section.Severity])+1)
+			sections[section.Severity] = append(sections[section.Severity], numberedIssue{
+				Issue:    issue,
+				Number:   number,
+				Anchor:   anchors.anchor("[" + number + "] " + issue.Title),
+				Findings: findings,
+			})
+		}
+	}
+
+	buf := strings.Builder{}
+	buf.WriteString("# " + title + "\n")
+	buf.WriteString("\n")
+	if r.Metadata.Project != "" {
+		buf.WriteString("Project: " + r.Metadata.Project + "\n")
+	}
+	if r.Metadata.Author != "" {
+		buf.WriteString("Author: " + r.Metadata.Author + "\n")
+	}
+	if r.Metadata.Project != "" || r.Metadata.Author != "" {
+		buf.WriteString("\n")
+	}
+
+	// Summary
+	buf.WriteString("## Summary\n")
+	buf.WriteString("\n")
+	found := false
+	for _, section := range c4Sections {
+		issues := sections[section.Severity]
+		if len(issues) == 0 {
+			continue
+		}
+		found = true
+
+		instances := 0
+		buf.WriteString("### " + section.Heading + "\n")
+		buf.WriteString("\n")
+		buf.WriteString("| |Issue|Instances|\n")
+		buf.WriteString("|-|:-|:-:|\n")
+		for _, issue := range issues {
+			buf.WriteString(fmt.Sprintf(
+				"| [%s](#%s) | %s | %d |\n",
+				issue.Number,
+				issue.Anchor,
+				strings.ReplaceAll(issue.Title, "|", "\\|"),
+				len(issue.Findings),
+			))
+			instances += len(issue.Findings)
+		}
+		buf.WriteString("\n")
+		buf.WriteString(fmt.Sprintf("Total: %s over %s\n", plural(instances, "instance"), plural(len(issues), "issue")))
+		buf.WriteString("\n")
+	}
+	if !found {
+		buf.WriteString("No issues found.\n")
+		return buf.String()
+	}
+
+	// Issues
+	for _, section := range c4Sections {
+		issues := sections[section.Severity]
+		if len(issues) == 0 {
+			continue
+		}
+
+		buf.WriteString("## " + section.Heading + "\n")
+		buf.WriteString("\n")
+		for _, issue := range issues {
+			buf.WriteString("### [" + issue.Number + "] " + issue.Title + "\n")
+			if issue.Impact != "" {
+				buf.WriteString(issue.Impact + "\n")
+				buf.WriteString("\n")
+			}
+			buf.WriteString(fmt.Sprintf("*Instances (%d):*\n", len(issue.Findings)))
+			buf.WriteString(r.markdownFindings(issue.Findings, opts))
+			if issue.Recommendation != "" {
+				buf.WriteString("\n")
+				buf.WriteString("**Recommendation:** " + issue.Recommendation + "\n")
+			}
+			buf.WriteString("\n")
+		}
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// plural returns `n` followed by `noun`, in plural unless `n` is 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// anchors generates the anchors GitHub gives to the headings of a Markdown
+// document.
+type anchors map[string]int
+
+func newAnchors() anchors {
+	return make(anchors)
+}
+
+// anchor returns the anchor of the next heading `heading`: the heading in
+// lower case, without punctuation and with spaces replaced by hyphens.
+// Repeated headings get the suffixes -1, -2 and so on.
+func (a anchors) anchor(heading string) string {
+	slug := strings.Builder{}
+	for _, c := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case c == ' ':
+			slug.WriteRune('-')
+		case c == '-' || c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c):
+			slug.WriteRune(c)
+		}
+	}
+
+	anchor := slug.String()
+	count := a[anchor]
+	a[anchor]++
+	if count > 0 {
+		anchor = fmt.Sprintf("%s-%d", anchor, count)
+	}
+	return anchor
+}
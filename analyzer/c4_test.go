@@ -0,0 +1,87 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestC4Markdown(t *testing.T) {
+	report := Report{
+		Issues: []Issue{
+			{Identifier: "G-01", Severity: GASOP, Title: "Cache `array.length`"},
+			{Identifier: "L-02", Severity: LOW, Title: "Unspecific pragma", Recommendation: "Pin the version."},
+			{Identifier: "L-03", Severity: LOW, Title: "No findings"},
+			{Identifier: "N-01", Severity: NC, Title: "A | B"},
+			{Identifier: "L-05", Severity: LOW, Title: "Unspecific pragma"},
+			{Identifier: "G-06", Severity: GASOP, Title: "Use `++i`"},
+		},
+		FindingsPerIssue: map[string][]Finding{
+			"G-01": {{File: "A.sol", LineNumber: 1, LineContent: "a"}},
+			"L-02": {{File: "A.sol", LineNumber: 2, LineContent: "b"}, {File: "A.sol", LineNumber: 3, LineContent: "c"}},
+			"N-01": {{File: "A.sol", LineNumber: 4, LineContent: "d"}},
+			"L-05": {{File: "A.sol", LineNumber: 5, LineContent: "e"}},
+			"G-06": {{File: "A.sol", LineNumber: 6, LineContent: "f"}},
+		},
+	}
+
+	qa := report.QAMarkdown(RenderOptions{})
+	wantQA := []string{
+		"# QA Report\n",
+		"### Low Risk Issues\n\n| |Issue|Instances|\n|-|:-|:-:|\n" +
+			"| [L-01](#l-01-unspecific-pragma) | Unspecific pragma | 2 |\n" +
+			"| [L-02](#l-02-unspecific-pragma) | Unspecific pragma | 1 |\n\n" +
+			"Total: 3 instances over 2 issues\n",
+		"| [N-01](#n-01-a--b) | A \\| B | 1 |\n",
+		"### [L-01] Unspecific pragma\n*Instances (2):*\n```solidity\nA.sol::2 => b\nA.sol::3 => c\n```\n\n**Recommendation:** Pin the version.\n",
+		"### [L-02] Unspecific pragma\n",
+		"### [N-01] A | B\n",
+	}
+	for _, want := range wantQA {
+		if !strings.Contains(qa, want) {
+			t.Errorf("QA report misses\n%s\ngot\n%s", want, qa)
+		}
+	}
+	if strings.Contains(qa, "G-") || strings.Contains(qa, "No findings") {
+		t.Errorf("QA report contains gas issues or issues without findings:\n%s", qa)
+	}
+
+	gas := report.GasMarkdown(RenderOptions{})
+	for _, want := range []string{
+		"# Gas Report\n",
+		"| [G-01](#g-01-cache-arraylength) | Cache `array.length` | 1 |\n| [G-02](#g-02-use-i) | Use `++i` | 1 |\n",
+		"### [G-02] Use `++i`\n",
+	} {
+		if !strings.Contains(gas, want) {
+			t.Errorf("gas report misses\n%s\ngot\n%s", want, gas)
+		}
+	}
+	if regexp.MustCompile(`\[[LN]-\d+\]`).MatchString(gas) {
+		t.Errorf("gas report contains QA issues:\n%s", gas)
+	}
+
+	empty := Report{Issues: report.Issues, FindingsPerIssue: map[string][]Finding{}}
+	if got := empty.GasMarkdown(RenderOptions{}); !strings.HasSuffix(got, "No issues found.\n") {
+		t.Errorf("got\n%s", got)
+	}
+}
+
+func TestAnchors(t *testing.T) {
+	a := newAnchors()
+	tests := []struct {
+		heading string
+		want    string
+	}{
+		{"[G-05] `++i` costs less gas compared to `i++` or `i += 1`", "g-05-i-costs-less-gas-compared-to-i-or-i--1"},
+		{"[L-01] Use of `ecrecover()` (ÄÖ)", "l-01-use-of-ecrecover-äö"},
+		{"Impact", "impact"},
+		{"Impact", "impact-1"},
+		{"Impact", "impact-2"},
+		{"snake_case", "snake_case"},
+	}
+	for _, test := range tests {
+		if got := a.anchor(test.heading); got != test.want {
+			t.Errorf("anchor(%q) = %q, want %q", test.heading, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package analyzer
+
+import (
+	"encoding/xml"
+	"sort"
+)
+
+// checkstyleVersion is the Checkstyle version whose format is written.
+const checkstyleVersion = "4.3"
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name      string            `xml:"name,attr"`
+	Errors    []checkstyleError `xml:"error"`
+	Exception string            `xml:"exception,omitempty"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// checkstyleSeverity returns the Checkstyle severity of findings of
+// severity `s`.
+func (s Severity) checkstyleSeverity() string {
+	if s == LOW {
+		return "warning"
+	}
+	return "info"
+}
+
+// Checkstyle returns the report in the Checkstyle XML format read by CI
+// servers. Every analyzed file lists its findings as errors, sorted by
+// line, with the source `c4udit.<issue>`. Paths that could not be analyzed
+// are files with an exception.
+func (r Report) Checkstyle() ([]byte, error) {
+	out := checkstyleReport{Version: checkstyleVersion}
+
+	files := make(map[string]*checkstyleFile)
+	names := []string{}
+	file := func(name string) *checkstyleFile {
+		if f, ok := files[name]; ok {
+			return f
+		}
+		files[name] = &checkstyleFile{Name: name}
+		names = append(names, name)
+		return files[name]
+	}
+	for _, name := range r.FilesAnalyzed {
+		file(name)
+	}
+
+	for _, issue := range r.Issues {
+		for _, f := range r.FindingsPerIssue[issue.Identifier] {
+			file(f.File).Errors = append(file(f.File).Errors, checkstyleError{
+				Line:     f.LineNumber,
+				Column:   f.Column,
+				Severity: issue.Severity.checkstyleSeverity(),
+				Message:  issue.Title,
+				Source:   "c4udit." + issue.Identifier,
+			})
+		}
+	}
+	for _, e := range r.Errors {
+		file(e.Path).Exception = e.Message
+	}
+
+	for _, name := range names {
+		f := files[name]
+		sort.SliceStable(f.Errors, func(i, j int) bool {
+			if f.Errors[i].Line != f.Errors[j].Line {
+				return f.Errors[i].Line < f.Errors[j].Line
+			}
+			return f.Errors[i].Column < f.Errors[j].Column
+		})
+		out.Files = append(out.Files, *f)
+	}
+
+	return marshalXML(out)
+}
+
+// marshalXML returns `v` as an indented XML document.
+func marshalXML(v interface{}) ([]byte, error) {
+	content, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), content...), nil
+}
@@ -0,0 +1,136 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// validateXML checks `data` against the XSD `schema` in testdata with
+// xmllint. The test is skipped if xmllint is not installed.
+func validateXML(t *testing.T, schema string, data []byte) {
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Skip("xmllint not installed, skipping schema validation")
+	}
+	path := filepath.Join(t.TempDir(), "report.xml")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(xmllint, "--noout", "--schema", filepath.Join("testdata", schema), path).CombinedOutput()
+	if err != nil {
+		t.Errorf("report does not match %s: %s", schema, out)
+	}
+}
+
+// Checkstyle publishes no schema, testdata/checkstyle.xsd is our own
+// reading of its XMLLogger. TestCheckstyleConsumerAttributes checks the
+// attributes that consumers, e.g. the Jenkins Warnings plugin, read
+// independently of it.
+func TestCheckstyleSchema(t *testing.T) {
+	data, err := fixtureReport(t, "Missing.sol").Checkstyle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validateXML(t, "checkstyle.xsd", data)
+}
+
+func TestCheckstyleConsumerAttributes(t *testing.T) {
+	data, err := fixtureReport(t, "Missing.sol").Checkstyle()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	required := map[string][]string{
+		"checkstyle": {"version"},
+		"file":       {"name"},
+		"error":      {"line", "column", "severity", "message", "source"},
+	}
+	severities := map[string]bool{"ignore": true, "info": true, "warning": true, "error": true}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	errors := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		attrs := make(map[string]string)
+		for _, attr := range start.Attr {
+			attrs[attr.Name.Local] = attr.Value
+		}
+		for _, name := range required[start.Name.Local] {
+			if attrs[name] == "" {
+				t.Errorf("<%s> misses attribute %s: %v", start.Name.Local, name, attrs)
+			}
+		}
+		if start.Name.Local != "error" {
+			continue
+		}
+		errors++
+		if !severities[attrs["severity"]] {
+			t.Errorf("invalid severity %q", attrs["severity"])
+		}
+		for _, name := range []string{"line", "column"} {
+			if n, err := strconv.Atoi(attrs[name]); err != nil || n < 1 {
+				t.Errorf("invalid %s %q", name, attrs[name])
+			}
+		}
+	}
+	if errors == 0 {
+		t.Errorf("no errors in %s", data)
+	}
+}
+
+func TestCheckstyle(t *testing.T) {
+	report := fixtureReport(t, "Missing.sol")
+	data, err := report.Checkstyle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed := checkstyleReport{}
+	if err := xml.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Files) != 2 || parsed.Files[0].Name != "A.sol" || parsed.Files[1].Name != "Missing.sol" {
+		t.Fatalf("got files %+v", parsed.Files)
+	}
+	if parsed.Files[1].Exception == "" {
+		t.Errorf("missing exception for Missing.sol")
+	}
+
+	findings := 0
+	for _, issue := range report.Issues {
+		findings += len(report.FindingsPerIssue[issue.Identifier])
+	}
+	errors := parsed.Files[0].Errors
+	if len(errors) != findings {
+		t.Errorf("got %d errors for %d findings", len(errors), findings)
+	}
+	for i, e := range errors {
+		if i > 0 && e.Line < errors[i-1].Line {
+			t.Errorf("errors not sorted by line: %+v", errors)
+		}
+		id := e.Source[len("c4udit."):]
+		severity := "info"
+		for _, issue := range report.Issues {
+			if issue.Identifier == id && issue.Severity == LOW {
+				severity = "warning"
+			}
+		}
+		if e.Severity != severity {
+			t.Errorf("%s: got severity %s, want %s", e.Source, e.Severity, severity)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"strings"
+)
+
+// formulaPrefixes are the first characters that make spreadsheets evaluate
+// a cell as formula.
+const formulaPrefixes = "=+-@\t\r"
+
+// csvHeader are the columns of the CSV report format. The status and notes
+// columns are left empty for reviewers.
+var csvHeader = []string{
+	"id",
+	"severity",
+	"title",
+	"file",
+	"line",
+	"column",
+	"line content",
+	"status",
+	"notes",
+	"fingerprint",
+}
+
+// CSV returns the report's findings as CSV, one row per Finding, for triage
+// in spreadsheets. See Triage for reading the edited file back. Cells that
+// spreadsheets would evaluate as formula, e.g. a line content starting with
+// `-`, are prefixed with `'`.
+func (r Report) CSV() ([]byte, error) {
+	buf := bytes.Buffer{}
+	w := csv.NewWriter(&buf)
+	if err := w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+
+	for _, issue := range r.Issues {
+		for _, f := range r.FindingsPerIssue[issue.Identifier] {
+			err := w.Write([]string{
+				csvCell(issue.Identifier),
+				issue.Severity.String(),
+				csvCell(issue.Title),
+				csvCell(f.File),
+				strconv.Itoa(f.LineNumber),
+				strconv.Itoa(f.Column),
+				csvCell(f.LineContent),
+				"",
+				"",
+				r.Fingerprint(f),
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// csvCell escapes `s` so that spreadsheets show it as text. Cells starting
+// with `'` are escaped too, so that unescapeCSVCell can tell them apart.
+func csvCell(s string) string {
+	if s != "" && strings.ContainsAny(s[:1], formulaPrefixes+"'") {
+		return "'" + s
+	}
+	return s
+}
+
+// unescapeCSVCell reverts csvCell.
+func unescapeCSVCell(s string) string {
+	if len(s) > 1 && s[0] == '\'' && strings.ContainsAny(s[1:2], formulaPrefixes+"'") {
+		return s[1:]
+	}
+	return s
+}
@@ -0,0 +1,231 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/byterocket/c4udit/analyzer/solidity"
+)
+
+var (
+	erc20CallPattern = regexp.MustCompile(`\.\s*(transfer|transferFrom|approve)\s*\(`)
+
+	// A call receiver such as `token`, `tokens[i]` or `IERC20(address(t))`.
+	receiverPattern = regexp.MustCompile(`^[A-Za-z_$][\w$]*(\s*\((?:[^()]|\([^()]*\))*\)|\s*\[[^\[\]]*\]|\s*\.\s*[A-Za-z_$][\w$]*)*$`)
+
+	// The header of an `if`, `for` or `while` statement, possibly after an
+	// `else`, whose body may be a single call.
+	controlHeaderPattern = regexp.MustCompile(`^(?:else\s+)?(?:if|for|while)\s*\(`)
+
+	compoundAssignmentPattern = regexp.MustCompile(`(?:^|[^\w$.])([A-Za-z_$][\w$]*)(?:\s*\[[^\]]*\]|\s*\.\s*[A-Za-z_$][\w$]*)*\s*(\+=|-=)`)
+
+	// An assignment to a variable, or an element or member of it.
+	assignmentPattern = regexp.MustCompile(`(?:^|[^\w$.])([A-Za-z_$][\w$]*)(?:\s*\[[^\]]*\]|\s*\.\s*[A-Za-z_$][\w$]*)*\s*(?:[-+*/%|&^]|<<|>>)?=(?:[^=]|$)`)
+)
+
+// detectUncheckedERC20 finds ERC20 `transfer`, `transferFrom` and `approve`
+// calls whose return value is discarded: calls that are a whole statement,
+// also as the single statement body of an `if`, `else`, `for`, `while` or
+// `do`. Single argument `transfer` calls are ether transfers and are
+// ignored.
+func detectUncheckedERC20(file *SourceFile) []Finding {
+	code := file.Code()
+
+	findings := []Finding{}
+	for _, s := range file.statements {
+		text := flatten(code[s.start:s.end])
+
+		for _, loc := range erc20CallPattern.FindAllStringSubmatchIndex(text, -1) {
+			receiver := receiverStart(text, loc[0])
+			if receiver == -1 || !discardsValue(strings.TrimSpace(text[:receiver])) {
+				continue
+			}
+
+			args := countArguments(text[loc[1]:])
+			if text[loc[2]:loc[3]] == "transfer" && args == 1 {
+				continue
+			}
+
+			findings = append(findings, file.Finding(s.start+receiver, s.start+loc[1]))
+		}
+	}
+
+	return findings
+}
+
+// receiverStart returns the offset of the longest call receiver, e.g.
+// `tokens[i]`, ending at offset `end` of `text`, or -1 if there is none.
+func receiverStart(text string, end int) int {
+	for i := 0; i < end; i++ {
+		if isSpace(text[i]) || (i > 0 && isIdentifierByte(text[i-1])) {
+			continue
+		}
+		if receiverPattern.MatchString(strings.TrimSpace(text[i:end])) {
+			return i
+		}
+	}
+	return -1
+}
+
+// discardsValue reports whether an expression following `prefix` in its
+// statement is a statement of its own, so that its value is discarded.
+func discardsValue(prefix string) bool {
+	switch prefix {
+	case "", "else", "do":
+		return true
+	}
+
+	// The expression is the body of a control statement if the header's
+	// parentheses close right before it.
+	loc := controlHeaderPattern.FindStringIndex(prefix)
+	if loc == nil {
+		return false
+	}
+	return closingParenthesis(prefix, loc[1]) == len(prefix)-1
+}
+
+// closingParenthesis returns the offset of the `)` closing the parenthesis
+// opened right before offset `start` of `s`, or -1.
+func closingParenthesis(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// detectStateVariableCompoundAssignment finds `x += y` and `x -= y` in
+// function bodies where `x` is a state variable of the function's contract or
+// one of its bases in the same file, and not shadowed by a parameter.
+func detectStateVariableCompoundAssignment(file *SourceFile) []Finding {
+	code := file.Code()
+
+	findings := []Finding{}
+	for _, contract := range file.Unit.Contracts {
+		stateVariables := make(map[string]bool)
+		collectStateVariables(file.Unit, contract, stateVariables, make(map[string]bool))
+
+		for _, function := range contract.Functions {
+			shadowed := make(map[string]bool)
+			for _, p := range append(function.Parameters, function.Returns...) {
+				shadowed[p.Name] = true
+			}
+
+			for _, s := range function.Statements {
+				text := flatten(code[s.Range.Start:s.Range.End])
+				for _, loc := range compoundAssignmentPattern.FindAllStringSubmatchIndex(text, -1) {
+					name := text[loc[2]:loc[3]]
+					if stateVariables[name] && !shadowed[name] {
+						findings = append(findings, file.Finding(s.Range.Start+loc[2], s.Range.Start+loc[1]))
+					}
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+// detectMemoryParameters finds `memory` parameters of implemented `external`
+// functions that are never assigned to in the function body.
+func detectMemoryParameters(file *SourceFile) []Finding {
+	code := file.Code()
+
+	findings := []Finding{}
+	for _, contract := range file.Unit.Contracts {
+		for _, function := range contract.Functions {
+			if function.Visibility != "external" || function.Body == nil {
+				continue
+			}
+
+			assigned := assignedNames(flatten(code[function.Body.Start:function.Body.End]))
+			for _, p := range function.Parameters {
+				if p.DataLocation != "memory" || (p.Name != "" && assigned[p.Name]) {
+					continue
+				}
+				findings = append(findings, file.Finding(p.Range.Start, p.Range.End))
+			}
+		}
+	}
+
+	return findings
+}
+
+// collectStateVariables adds the mutable state variables of `contract` and
+// its bases defined in `unit` to `variables`.
+func collectStateVariables(unit *solidity.SourceUnit, contract *solidity.Contract, variables map[string]bool, visited map[string]bool) {
+	if visited[contract.Name] {
+		return
+	}
+	visited[contract.Name] = true
+
+	for _, v := range contract.StateVariables {
+		if v.Mutability == "" {
+			variables[v.Name] = true
+		}
+	}
+
+	for _, base := range contract.Bases {
+		for _, c := range unit.Contracts {
+			if c.Name == base {
+				collectStateVariables(unit, c, variables, visited)
+			}
+		}
+	}
+}
+
+// assignedNames returns the variables that are, or whose elements or
+// members are, assigned to in `code`.
+func assignedNames(code string) map[string]bool {
+	names := make(map[string]bool)
+	for i := 0; i < len(code); {
+		loc := assignmentPattern.FindStringSubmatchIndex(code[i:])
+		if loc == nil {
+			break
+		}
+		names[code[i+loc[2]:i+loc[3]]] = true
+		// Continue after the variable, so that chained assignments such
+		// as `a = b = c` are found too.
+		i += loc[3]
+	}
+	return names
+}
+
+// countArguments returns the number of arguments of a call, given the source
+// following the call's opening parenthesis.
+func countArguments(s string) int {
+	args := 0
+	depth := 0
+	for _, c := range s {
+		if depth == 0 && c == ')' {
+			return args
+		}
+		if args == 0 && c != ' ' {
+			args = 1
+		}
+
+		switch c {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				args++
+			}
+		}
+	}
+	return args
+}
@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const detectorSource = `pragma solidity 0.8.10;
+contract C {
+    uint256 public total;
+    mapping(address => uint256) balances;
+    function f(IERC20 token, uint256 amount) external {
+        uint256 local;
+        local += 1;
+        total += amount;
+        balances[msg.sender] -= amount;
+        token.transfer(msg.sender, amount);
+        require(token.transfer(msg.sender, amount));
+        bool ok = token.approve(msg.sender, amount);
+        payable(msg.sender).transfer(amount);
+        tokens[i].approve(
+            spender,
+            amount
+        );
+    }
+}
+`
+
+func TestDetectors(t *testing.T) {
+	file := sourceFile(t, detectorSource)
+
+	tests := []struct {
+		detect DetectorFunc
+		lines  []int
+	}{
+		{detectUncheckedERC20, []int{10, 14}},
+		{detectStateVariableCompoundAssignment, []int{8, 9}},
+	}
+
+	for _, test := range tests {
+		lines := []int{}
+		for _, f := range test.detect(file) {
+			lines = append(lines, f.LineNumber)
+		}
+		if !reflect.DeepEqual(lines, test.lines) {
+			t.Errorf("got findings on lines %v, want %v", lines, test.lines)
+		}
+	}
+}
+
+func TestDetectUncheckedERC20(t *testing.T) {
+	tests := []struct {
+		statement string
+		unchecked bool
+	}{
+		{"token.transfer(to, amount);", true},
+		{"IERC20(address(t)).transferFrom(from, to, amount);", true},
+		{"if (ok) token.transfer(to, amount);", true},
+		{"if (a) { x = 1; } else token.transferFrom(from, to, amount);", true},
+		{"if (a) x = 1; else if (b) tokens[i].approve(spender, amount);", true},
+		{"for (uint256 i; i < n; i++) token.transfer(to[i], amount);", true},
+		{"while (f(x)) token.transfer(to, amount);", true},
+		{"do token.transfer(to, amount); while (x);", true},
+		{"bool ok = token.transfer(to, amount);", false},
+		{"ok = token.transfer(to, amount);", false},
+		{"require(token.transfer(to, amount));", false},
+		{"return token.transfer(to, amount);", false},
+		{"if (token.transfer(to, amount)) x = 1;", false},
+		{"if (!token.transfer(to, amount)) revert();", false},
+		{"if (a && token.approve(spender, amount)) x = 1;", false},
+		{"if (ok) payable(to).transfer(amount);", false},
+	}
+
+	for _, test := range tests {
+		source := "contract C {\n    function f() external {\n        " + test.statement + "\n    }\n}\n"
+		findings := detectUncheckedERC20(sourceFile(t, source))
+		if got := len(findings) > 0; got != test.unchecked {
+			t.Errorf("%s: got unchecked %v, want %v", test.statement, got, test.unchecked)
+		}
+		for _, f := range findings {
+			if !erc20CallPattern.MatchString(f.Match) || strings.HasPrefix(f.Match, "if") || strings.HasPrefix(f.Match, "else") {
+				t.Errorf("%s: got match %q, want the call only", test.statement, f.Match)
+			}
+		}
+	}
+}
+
+func TestAssignedNames(t *testing.T) {
+	code := "a = 1; b[i] += 2; c.d = e; f == g; h <= i; j >>= 1; k = l = m; n.push(o);"
+	want := map[string]bool{"a": true, "b": true, "c": true, "j": true, "k": true, "l": true}
+	if got := assignedNames(code); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,214 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	scpURLPattern = regexp.MustCompile(`^[\w.-]+@([\w.-]+):(.+)$`)
+	originSection = regexp.MustCompile(`^\[remote\s+"origin"\]$`)
+	commitPattern = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+)
+
+// Repository locates the files of a Report in a hosted git repository, to
+// link findings to their source.
+type Repository struct {
+	// URL of the repository, e.g. https://github.com/org/repo.
+	URL string
+	// Commit the files are linked at.
+	Commit string
+	// Root is the repository's working tree. Report paths are made relative
+	// to it, they are used as is if Root is empty.
+	Root string
+}
+
+// Permalink returns the link to lines `start` to `end` of the file at
+// slash-separated `path` relative to the repository root, e.g.
+// https://github.com/org/repo/blob/<commit>/src/A.sol#L6-L8.
+func (repo Repository) Permalink(path string, start, end int) string {
+	link := repo.URL + "/blob/" + repo.Commit + "/" + (&url.URL{Path: path}).EscapedPath()
+	if start > 0 {
+		link += fmt.Sprintf("#L%d", start)
+		if end > start {
+			link += fmt.Sprintf("-L%d", end)
+		}
+	}
+	return link
+}
+
+// permalink returns the link to finding `f` of report `r` in `repo`, or an
+// empty string if the file is not in the repository.
+func (r Report) permalink(repo *Repository, f Finding) string {
+	path := f.File
+	if repo.Root != "" {
+		abs := path
+		if !filepath.IsAbs(abs) {
+			abs = filepath.Join(r.Root, filepath.FromSlash(path))
+		}
+		rel, err := filepath.Rel(repo.Root, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return ""
+		}
+		path = filepath.ToSlash(rel)
+	}
+	return repo.Permalink(path, f.LineNumber, f.EndLineNumber)
+}
+
+// NormalizeRepoURL returns the web URL of the git remote `remote`, e.g.
+// https://github.com/org/repo for git@github.com:org/repo.git.
+func NormalizeRepoURL(remote string) string {
+	remote = strings.TrimSpace(remote)
+	if m := scpURLPattern.FindStringSubmatch(remote); m != nil && !strings.Contains(remote, "://") {
+		remote = "https://" + m[1] + "/" + m[2]
+	} else if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		switch u.Scheme {
+		case "ssh", "git", "git+ssh", "http":
+			u.Scheme = "https"
+		}
+		u.User = nil
+		if u.Scheme == "https" {
+			// Drop the port of SSH remotes.
+			u.Host = u.Hostname()
+		}
+		remote = u.String()
+	}
+	remote = strings.TrimSuffix(remote, "/")
+	return strings.TrimSuffix(remote, ".git")
+}
+
+// DetectRepository returns the Repository of the git working tree
+// containing `dir`, with the URL of its origin remote and the commit
+// checked out. Fields that can not be detected are left empty.
+func DetectRepository(dir string) (Repository, error) {
+	root, gitDir, err := findGitDir(dir)
+	if err != nil {
+		return Repository{}, err
+	}
+	repo := Repository{Root: root}
+
+	// Worktrees share the config and refs of the main repository.
+	commonDir := gitDir
+	if content, err := ioutil.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir = strings.TrimSpace(string(content))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+	}
+
+	if remote := readOriginURL(filepath.Join(commonDir, "config")); remote != "" {
+		repo.URL = NormalizeRepoURL(remote)
+	}
+	repo.Commit = readHead(gitDir, commonDir)
+	return repo, nil
+}
+
+// findGitDir returns the working tree containing `dir` and its git
+// directory.
+func findGitDir(dir string) (string, string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	for current := abs; ; current = filepath.Dir(current) {
+		gitPath := filepath.Join(current, ".git")
+		info, err := os.Stat(gitPath)
+		if err == nil && info.IsDir() {
+			return current, gitPath, nil
+		}
+		if err == nil {
+			// Worktrees and submodules have a .git file pointing to the
+			// git directory.
+			content, err := ioutil.ReadFile(gitPath)
+			if err != nil {
+				return "", "", err
+			}
+			gitDir := strings.TrimSpace(strings.TrimPrefix(string(content), "gitdir:"))
+			if !filepath.IsAbs(gitDir) {
+				gitDir = filepath.Join(current, gitDir)
+			}
+			return current, gitDir, nil
+		}
+		if filepath.Dir(current) == current {
+			return "", "", fmt.Errorf("%s is not in a git repository", dir)
+		}
+	}
+}
+
+// readOriginURL returns the URL of the origin remote in the git config
+// file at `path`, or an empty string.
+func readOriginURL(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	inOrigin := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inOrigin = originSection.MatchString(line)
+			continue
+		}
+		if !inOrigin {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) == "url" {
+			return strings.TrimSpace(parts[1])
+		}
+	}
+	return ""
+}
+
+// readHead returns the commit checked out in git directory `gitDir`, or an
+// empty string.
+func readHead(gitDir string, commonDir string) string {
+	content, err := ioutil.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+	head := strings.TrimSpace(string(content))
+	if !strings.HasPrefix(head, "ref:") {
+		// Detached HEAD.
+		return validCommit(head)
+	}
+
+	ref := strings.TrimSpace(strings.TrimPrefix(head, "ref:"))
+	for _, dir := range []string{gitDir, commonDir} {
+		if content, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(ref))); err == nil {
+			return validCommit(strings.TrimSpace(string(content)))
+		}
+	}
+
+	// Refs may be packed, one "<commit> <ref>" per line.
+	content, err = ioutil.ReadFile(filepath.Join(commonDir, "packed-refs"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == ref {
+			return validCommit(fields[0])
+		}
+	}
+	return ""
+}
+
+// validCommit returns `s` if it is a SHA-1 or SHA-256 commit hash, or an
+// empty string.
+func validCommit(s string) string {
+	if !commitPattern.MatchString(s) {
+		return ""
+	}
+	return s
+}
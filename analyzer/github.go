@@ -0,0 +1,131 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// GitHubAnnotationLimit is the number of annotations of each level GitHub
+// Actions shows per step. Further annotations are dropped by GitHub.
+const GitHubAnnotationLimit = 10
+
+// githubLevel returns the workflow command annotating findings of
+// severity `s`.
+func (s Severity) githubLevel() string {
+	if s == LOW {
+		return "warning"
+	}
+	return "notice"
+}
+
+// GitHub returns the report as GitHub Actions workflow commands, which
+// annotate the findings on the diff of pull requests. Low findings are
+// warnings, gas and non-critical findings notices.
+//
+// To stay within GitHubAnnotationLimit, the findings of each level are
+// annotated in turns of the issues, so every issue is shown. If findings
+// are left over, the last annotation of the level lists their number per
+// issue.
+//
+// Paths are relative to opts.Workspace if set, e.g. to the repository
+// checked out at $GITHUB_WORKSPACE, and to Root otherwise.
+func (r Report) GitHub(opts RenderOptions) string {
+	buf := strings.Builder{}
+
+	for _, level := range []string{"warning", "notice"} {
+		issues := []Issue{}
+		pending := make(map[string][]Finding)
+		total := 0
+		for _, issue := range r.Issues {
+			findings := r.FindingsPerIssue[issue.Identifier]
+			if issue.Severity.githubLevel() != level || len(findings) == 0 {
+				continue
+			}
+			issues = append(issues, issue)
+			pending[issue.Identifier] = findings
+			total += len(findings)
+		}
+
+		limit := GitHubAnnotationLimit
+		if total > limit {
+			// Keep the last annotation for the summary.
+			limit--
+		}
+		for annotated := 0; annotated < limit && annotated < total; {
+			for _, issue := range issues {
+				findings := pending[issue.Identifier]
+				if annotated == limit || len(findings) == 0 {
+					continue
+				}
+				buf.WriteString(r.githubAnnotation(level, issue, findings[0], opts))
+				pending[issue.Identifier] = findings[1:]
+				annotated++
+			}
+		}
+
+		if total > limit {
+			lines := []string{}
+			for _, issue := range issues {
+				if left := len(pending[issue.Identifier]); left > 0 {
+					lines = append(lines, fmt.Sprintf("[%s] %s: %d more", issue.Identifier, issue.Title, left))
+				}
+			}
+			buf.WriteString(fmt.Sprintf(
+				"::%s title=%s::%s\n",
+				level,
+				escapeGitHubProperty(fmt.Sprintf("c4udit: %d more findings not annotated", total-limit)),
+				escapeGitHubData(strings.Join(lines, "\n")),
+			))
+		}
+	}
+
+	return buf.String()
+}
+
+// githubAnnotation returns the workflow command annotating finding `f` of
+// `issue`.
+func (r Report) githubAnnotation(level string, issue Issue, f Finding, opts RenderOptions) string {
+	properties := []string{"file=" + escapeGitHubProperty(r.githubPath(f.File, opts))}
+	if f.LineNumber > 0 {
+		properties = append(properties, fmt.Sprintf("line=%d", f.LineNumber))
+		if f.EndLineNumber > f.LineNumber {
+			properties = append(properties, fmt.Sprintf("endLine=%d", f.EndLineNumber))
+		} else if f.Column > 0 && f.EndColumn > f.Column {
+			// Columns are only supported on single lines.
+			properties = append(properties, fmt.Sprintf("col=%d", f.Column), fmt.Sprintf("endColumn=%d", f.EndColumn))
+		}
+	}
+	properties = append(properties, "title="+escapeGitHubProperty(fmt.Sprintf("[%s] %s", issue.Identifier, issue.Title)))
+
+	message := issue.Recommendation
+	if message == "" {
+		message = issue.Title
+	}
+	return fmt.Sprintf("::%s %s::%s\n", level, strings.Join(properties, ","), escapeGitHubData(message))
+}
+
+// githubPath returns report path `path` relative to opts.Workspace.
+func (r Report) githubPath(path string, opts RenderOptions) string {
+	if opts.Workspace == "" {
+		return path
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.Root, filepath.FromSlash(path))
+	}
+	rel, err := filepath.Rel(opts.Workspace, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// escapeGitHubData escapes the message of a workflow command.
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeGitHubProperty escapes a property value of a workflow command.
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
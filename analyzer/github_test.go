@@ -0,0 +1,84 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGitHub(t *testing.T) {
+	findings := func(id string, n int) []Finding {
+		findings := []Finding{}
+		for i := 1; i <= n; i++ {
+			findings = append(findings, Finding{
+				IssueIdentifier: id,
+				File:            "src/A.sol",
+				LineNumber:      i,
+				EndLineNumber:   i,
+				Column:          5,
+				EndColumn:       8,
+			})
+		}
+		return findings
+	}
+
+	root := filepath.Join(string(filepath.Separator), "repo", "contracts")
+	report := Report{
+		Root: root,
+		Issues: []Issue{
+			{Identifier: "G-06", Severity: GASOP, Title: "Use `++i`", Recommendation: "Use `++i`, not `i++`.\n100%"},
+			{Identifier: "N-02", Severity: NC, Title: "Event is missing indexed fields"},
+			{Identifier: "L-01", Severity: LOW, Title: "Unsafe ERC20 Operation(s)"},
+		},
+		FindingsPerIssue: map[string][]Finding{
+			"G-06": findings("G-06", 30),
+			"N-02": findings("N-02", 2),
+			"L-01": findings("L-01", 1),
+		},
+	}
+
+	out := report.GitHub(RenderOptions{Workspace: filepath.Join(string(filepath.Separator), "repo")})
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+
+	want := "::warning file=contracts/src/A.sol,line=1,col=5,endColumn=8,title=[L-01] Unsafe ERC20 Operation(s)::Unsafe ERC20 Operation(s)"
+	if lines[0] != want {
+		t.Errorf("got\n%s\nwant\n%s", lines[0], want)
+	}
+	want = "::notice file=contracts/src/A.sol,line=1,col=5,endColumn=8,title=[G-06] Use `++i`::Use `++i`, not `i++`.%0A100%25"
+	if lines[1] != want {
+		t.Errorf("got\n%s\nwant\n%s", lines[1], want)
+	}
+
+	// Both issues are annotated in turns, the overflow is summarized.
+	notices := 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, "::notice ") {
+			notices++
+		}
+	}
+	if notices != GitHubAnnotationLimit {
+		t.Errorf("got %d notices, want %d", notices, GitHubAnnotationLimit)
+	}
+	if !strings.Contains(out, "title=[N-02] Event is missing indexed fields::Event is missing indexed fields\n::notice file=contracts/src/A.sol,line=2") {
+		t.Errorf("issues not annotated in turns:\n%s", out)
+	}
+	summary := lines[len(lines)-1]
+	wantSummary := fmt.Sprintf("::notice title=c4udit%%3A %d more findings not annotated::[G-06] Use `++i`: %d more", 32-9, 30-7)
+	if summary != wantSummary {
+		t.Errorf("got summary\n%s\nwant\n%s", summary, wantSummary)
+	}
+
+	// Without a workspace, paths are relative to the root.
+	out = report.GitHub(RenderOptions{})
+	if !strings.HasPrefix(out, "::warning file=src/A.sol,") {
+		t.Errorf("got %s", strings.SplitN(out, "\n", 2)[0])
+	}
+}
+
+func TestEscapeGitHubProperty(t *testing.T) {
+	got := escapeGitHubProperty("a:b,c%d\ne")
+	if want := "a%3Ab%2Cc%25d%0Ae"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// globCache holds the compiled regexps of globs by pattern.
+var globCache sync.Map
+
+// MatchGlob reports whether the slash-separated `path` matches the glob
+// `pattern`. `*` matches within a path segment, `**` matches across
+// segments and `?` matches a single character. Patterns without a slash
+// match any single segment of the path, such as a directory or file name,
+// other patterns match the whole path. A leading "/" or "./" anchors a
+// pattern to the start of the path.
+func MatchGlob(pattern string, path string) bool {
+	if strings.HasPrefix(pattern, "./") {
+		pattern = pattern[1:]
+	}
+	path = strings.TrimPrefix(path, "./")
+
+	if !strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+		re := globRegexp(strings.TrimSuffix(pattern, "/"))
+		for _, segment := range strings.Split(path, "/") {
+			if re.MatchString(segment) {
+				return true
+			}
+		}
+		return false
+	}
+
+	pattern = strings.TrimPrefix(pattern, "/")
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	return globRegexp(pattern).MatchString(path)
+}
+
+// globRegexp compiles a glob pattern into an anchored regexp.
+func globRegexp(pattern string) *regexp.Regexp {
+	if re, ok := globCache.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+
+	buf := strings.Builder{}
+	buf.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			buf.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			buf.WriteString(".*")
+			i++
+		case c == '*':
+			buf.WriteString("[^/]*")
+		case c == '?':
+			buf.WriteString("[^/]")
+		default:
+			buf.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	buf.WriteString("$")
+	re := regexp.MustCompile(buf.String())
+	globCache.Store(pattern, re)
+	return re
+}
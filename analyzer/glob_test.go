@@ -0,0 +1,32 @@
+package analyzer
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"test", "test/A.sol", true},
+		{"test/", "test/A.sol", true},
+		{"test", "src/test/A.sol", true},
+		{"test", "src/testing/A.sol", false},
+		{"*.t.sol", "test/A.t.sol", true},
+		{"*.t.sol", "src/A.sol", false},
+		{"src/*.sol", "src/A.sol", true},
+		{"src/*.sol", "src/lib/A.sol", false},
+		{"src/**/*.sol", "src/A.sol", true},
+		{"src/**/*.sol", "src/lib/deep/A.sol", true},
+		{"/src/", "src/lib/A.sol", true},
+		{"./src/", "lib/src/A.sol", false},
+		{"src/?.sol", "src/A.sol", true},
+		{"src/?.sol", "src/AB.sol", false},
+	}
+
+	for _, test := range tests {
+		if got := MatchGlob(test.pattern, test.path); got != test.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", test.pattern, test.path, got, test.want)
+		}
+	}
+}
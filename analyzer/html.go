@@ -0,0 +1,365 @@
+package analyzer
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/byterocket/c4udit/analyzer/solidity"
+)
+
+// htmlContextLines is the number of source lines shown before and after
+// each finding in HTML reports.
+const htmlContextLines = 3
+
+//go:embed html/report.html
+var htmlSource string
+
+var htmlTemplate = template.Must(template.New("report").Parse(htmlSource))
+
+var (
+	identifierPattern  = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*`)
+	numberPattern      = regexp.MustCompile(`^(0x[0-9a-fA-F_]+|[0-9][0-9_]*(\.[0-9_]+)?([eE]-?[0-9]+)?)`)
+	typePattern        = regexp.MustCompile(`^(u?int[0-9]*|u?fixed[0-9x]*|bytes[0-9]*|address|bool|string|byte|mapping)$`)
+	inlineCodePattern  = regexp.MustCompile("`([^`]+)`")
+	solidityKeywordSet = make(map[string]bool)
+)
+
+func init() {
+	for _, keyword := range strings.Fields(`
+		abstract anonymous assembly assert break calldata catch constant constructor
+		continue contract delete do else emit enum error event external
+		fallback false for function if immutable import indexed interface
+		internal is let library memory modifier new override payable pragma
+		private public pure receive require return returns revert storage struct
+		super this true try type unchecked using view virtual while`) {
+		solidityKeywordSet[keyword] = true
+	}
+}
+
+// htmlReport is the data of the HTML report template.
+type htmlReport struct {
+	Metadata      Metadata
+	Title         string
+	Version       string
+	Severities    []htmlSeverity
+	Issues        []htmlIssue
+	Files         []htmlFile
+	FilesAnalyzed []string
+	Errors        []AnalysisError
+	Suppressed    int
+	Baseline      *BaselineStats
+	Scope         string
+}
+
+type htmlSeverity struct {
+	Name     string
+	Label    string
+	Issues   int
+	Findings int
+}
+
+type htmlIssue struct {
+	Identifier     string
+	Severity       string
+	SeverityLabel  string
+	Title          template.HTML
+	Impact         template.HTML
+	Recommendation template.HTML
+	Findings       []htmlFinding
+}
+
+type htmlFile struct {
+	Name     string
+	Findings []htmlFinding
+}
+
+type htmlFinding struct {
+	Identifier string
+	Severity   string
+	Title      template.HTML
+	File       string
+	Location   string
+	Function   string
+	// Text is searched by the search field.
+	Text  string
+	Lines []htmlLine
+}
+
+type htmlLine struct {
+	Number int
+	Code   template.HTML
+	// Hit is set for the lines of the finding itself.
+	Hit bool
+}
+
+// HTML returns the report as a single HTML file with inlined styles and
+// scripts, so it can be viewed offline. Findings are grouped by issue and by
+// file, and can be filtered by severity, file and text. Snippets show the
+// lines around each finding, read from the files under Root. If a file can
+// not be read, only the finding's line content is shown.
+func (r Report) HTML() ([]byte, error) {
+	data := htmlReport{
+		Metadata:      r.Metadata,
+		Title:         r.Metadata.title(),
+		Version:       Version,
+		FilesAnalyzed: r.FilesAnalyzed,
+		Errors:        r.Errors,
+		Baseline:      r.Baseline,
+		Scope:         r.Scope,
+	}
+
+	sources := make(map[string]*htmlSourceFile)
+	severities := make(map[Severity]*htmlSeverity)
+	files := make(map[string]*htmlFile)
+	for _, s := range []Severity{LOW, NC, GASOP} {
+		severities[s] = &htmlSeverity{Name: s.shortName(), Label: s.String()}
+	}
+
+	for _, issue := range r.Issues {
+		data.Suppressed += r.SuppressedPerIssue[issue.Identifier]
+		findings := r.FindingsPerIssue[issue.Identifier]
+		if len(findings) == 0 {
+			continue
+		}
+
+		i := htmlIssue{
+			Identifier:     issue.Identifier,
+			Severity:       issue.Severity.shortName(),
+			SeverityLabel:  issue.Severity.String(),
+			Title:          inlineCode(issue.Title),
+			Impact:         inlineCode(issue.Impact),
+			Recommendation: inlineCode(issue.Recommendation),
+		}
+		for _, f := range findings {
+			source, ok := sources[f.File]
+			if !ok {
+				source = r.readSource(f.File)
+				sources[f.File] = source
+			}
+
+			finding := htmlFinding{
+				Identifier: issue.Identifier,
+				Severity:   i.Severity,
+				Title:      i.Title,
+				File:       f.File,
+				Location:   strings.TrimSuffix(f.location(), " => "),
+				Function:   f.Function,
+				Text:       strings.ToLower(strings.Join([]string{issue.Identifier, issue.Title, f.File, f.Function, f.LineContent}, " ")),
+				Lines:      source.snippet(f),
+			}
+			i.Findings = append(i.Findings, finding)
+
+			file, ok := files[f.File]
+			if !ok {
+				file = &htmlFile{Name: f.File}
+				files[f.File] = file
+			}
+			file.Findings = append(file.Findings, finding)
+		}
+		data.Issues = append(data.Issues, i)
+
+		severities[issue.Severity].Issues++
+		severities[issue.Severity].Findings += len(findings)
+	}
+
+	for _, s := range []Severity{LOW, NC, GASOP} {
+		data.Severities = append(data.Severities, *severities[s])
+	}
+
+	// Files in the order they were analyzed, then files only known from
+	// findings.
+	for _, name := range r.FilesAnalyzed {
+		if file, ok := files[name]; ok {
+			data.Files = append(data.Files, *file)
+			delete(files, name)
+		}
+	}
+	for _, issue := range r.Issues {
+		for _, f := range r.FindingsPerIssue[issue.Identifier] {
+			if file, ok := files[f.File]; ok {
+				data.Files = append(data.Files, *file)
+				delete(files, f.File)
+			}
+		}
+	}
+
+	buf := bytes.Buffer{}
+	if err := htmlTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// inlineCode escapes `s` and renders text in backticks as code.
+func inlineCode(s string) template.HTML {
+	escaped := template.HTMLEscapeString(s)
+	return template.HTML(inlineCodePattern.ReplaceAllString(escaped, "<code>$1</code>"))
+}
+
+// htmlSourceFile is a source file read to show the context of findings.
+type htmlSourceFile struct {
+	lexed *lexedSource
+	// starts are the byte offsets of the lines.
+	starts []int
+	lines  []string
+}
+
+// readSource reads the file at report path `path`, or returns nil.
+func (r Report) readSource(path string) *htmlSourceFile {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.Root, filepath.FromSlash(path))
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	source := &htmlSourceFile{lexed: lex(string(content))}
+	start := 0
+	for _, line := range strings.SplitAfter(strings.TrimSuffix(string(content), "\n"), "\n") {
+		source.starts = append(source.starts, start)
+		source.lines = append(source.lines, strings.TrimRight(line, "\r\n"))
+		start += len(line)
+	}
+	return source
+}
+
+// snippet returns the highlighted lines of finding `f` with the lines
+// around it. If the source is missing or changed since the analysis, only
+// the finding's line content is returned.
+func (s *htmlSourceFile) snippet(f Finding) []htmlLine {
+	end := f.EndLineNumber
+	if end < f.LineNumber {
+		end = f.LineNumber
+	}
+	if s == nil || f.LineNumber < 1 || end > len(s.lines) ||
+		!strings.HasPrefix(f.LineContent, strings.TrimSpace(s.lines[f.LineNumber-1])) {
+		return []htmlLine{lineContentSnippet(f)}
+	}
+
+	first := f.LineNumber - htmlContextLines
+	if first < 1 {
+		first = 1
+	}
+	last := end + htmlContextLines
+	if last > len(s.lines) {
+		last = len(s.lines)
+	}
+
+	lines := []htmlLine{}
+	for n := first; n <= last; n++ {
+		text := s.lines[n-1]
+		start := s.starts[n-1]
+		regions := s.lexed.regions[start : start+len(text)]
+
+		hit := n >= f.LineNumber && n <= end
+		markStart, markEnd := -1, -1
+		if hit && f.Column > 0 {
+			markStart, markEnd = 0, len(text)
+			if n == f.LineNumber {
+				markStart = byteOffset(text, f.Column)
+			}
+			if n == end && f.EndColumn > 0 {
+				markEnd = byteOffset(text, f.EndColumn)
+			}
+		}
+		lines = append(lines, htmlLine{
+			Number: n,
+			Code:   highlight(text, regions, markStart, markEnd),
+			Hit:    hit,
+		})
+	}
+	return lines
+}
+
+// lineContentSnippet returns the finding's line content as a single line.
+func lineContentSnippet(f Finding) htmlLine {
+	markStart, markEnd := -1, -1
+	if i := strings.Index(f.LineContent, f.Match); f.Match != "" && i >= 0 {
+		markStart, markEnd = i, i+len(f.Match)
+	}
+	return htmlLine{
+		Number: f.LineNumber,
+		Code:   highlight(f.LineContent, lex(f.LineContent).regions, markStart, markEnd),
+		Hit:    true,
+	}
+}
+
+// byteOffset returns the byte offset of 1-based character column `column`
+// in `line`.
+func byteOffset(line string, column int) int {
+	offset := 0
+	for i := 1; i < column && offset < len(line); i++ {
+		_, size := utf8.DecodeRuneInString(line[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// highlight returns the HTML of a source line. Comments, string literals,
+// keywords, types and numbers are wrapped in spans of classes c, s, k, t and
+// n, the bytes [markStart, markEnd) in a mark element.
+func highlight(line string, regions []solidity.Region, markStart, markEnd int) template.HTML {
+	classes := make([]string, len(line))
+	for i := 0; i < len(line); {
+		switch {
+		case regions[i] == solidity.COMMENT:
+			classes[i] = "c"
+			i++
+		case regions[i] == solidity.LITERAL || line[i] == '"' || line[i] == '\'':
+			classes[i] = "s"
+			i++
+		default:
+			class, length := "", 1
+			if m := identifierPattern.FindString(line[i:]); m != "" {
+				length = len(m)
+				if solidityKeywordSet[m] {
+					class = "k"
+				} else if typePattern.MatchString(m) {
+					class = "t"
+				}
+			} else if m := numberPattern.FindString(line[i:]); m != "" {
+				class, length = "n", len(m)
+			}
+			for j := i; j < i+length; j++ {
+				classes[j] = class
+			}
+			i += length
+		}
+	}
+
+	buf := strings.Builder{}
+	marked := false
+	for i := 0; i < len(line); {
+		if in := i >= markStart && i < markEnd; in != marked {
+			if in {
+				buf.WriteString("<mark>")
+			} else {
+				buf.WriteString("</mark>")
+			}
+			marked = in
+		}
+
+		// A run of bytes with the same class and mark.
+		j := i + 1
+		for j < len(line) && classes[j] == classes[i] && (j >= markStart && j < markEnd) == marked {
+			j++
+		}
+		text := template.HTMLEscapeString(line[i:j])
+		if classes[i] != "" {
+			text = `<span class="` + classes[i] + `">` + text + "</span>"
+		}
+		buf.WriteString(text)
+		i = j
+	}
+	if marked {
+		buf.WriteString("</mark>")
+	}
+	return template.HTML(buf.String())
+}
@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestHTML(t *testing.T) {
+	report := fixtureReport(t)
+	report.Metadata = Metadata{Title: "Audit <1>"}
+
+	data, err := report.HTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+
+	if !strings.Contains(content, "<title>Audit &lt;1&gt;</title>") {
+		t.Errorf("title not escaped")
+	}
+	// The report needs no network access.
+	if m := regexp.MustCompile(`(src|href)="(https?:)?//`).FindString(content); m != "" {
+		t.Errorf("report loads external resource: %s", m)
+	}
+
+	for _, issue := range report.Issues {
+		findings := report.FindingsPerIssue[issue.Identifier]
+		has := strings.Contains(content, `<details class="group" id="`+issue.Identifier+`">`)
+		if has != (len(findings) > 0) {
+			t.Errorf("%s: got section %v with %d findings", issue.Identifier, has, len(findings))
+		}
+		// Every finding is shown by issue and by file.
+		if got := strings.Count(content, `<a href="#`+issue.Identifier+`">`); got != 2*len(findings) {
+			t.Errorf("%s: got %d findings, want %d", issue.Identifier, got, 2*len(findings))
+		}
+	}
+
+	// Snippets show the lines around findings.
+	if !regexp.MustCompile(`<span class="line"><span class="ln">\d+</span>`).MatchString(content) {
+		t.Errorf("missing context lines")
+	}
+}
+
+func TestHTMLSnippet(t *testing.T) {
+	content := "contract A {\n    // x\n    function f() public {\n        i++; // y\n    }\n}\n"
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "A.sol"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	source := Report{Root: dir}.readSource("A.sol")
+
+	f := Finding{File: "A.sol", LineNumber: 4, EndLineNumber: 4, Column: 9, EndColumn: 12, Match: "i++", LineContent: "i++; // y"}
+	lines := source.snippet(f)
+	if len(lines) != 6 || lines[0].Number != 1 || lines[5].Number != 6 {
+		t.Fatalf("got lines %+v, want 1 to 6", lines)
+	}
+	if want := `        <mark>i++</mark>; <span class="c">// y</span>`; string(lines[3].Code) != want || !lines[3].Hit {
+		t.Errorf("got %q, want %q", lines[3].Code, want)
+	}
+	if lines[2].Hit {
+		t.Errorf("context line marked as hit")
+	}
+
+	// The file changed since the analysis.
+	f.LineContent = "j++;"
+	f.Match = "j++"
+	lines = source.snippet(f)
+	if len(lines) != 1 || string(lines[0].Code) != "<mark>j++</mark>;" {
+		t.Errorf("got lines %+v, want line content only", lines)
+	}
+
+	// The file is missing.
+	lines = (Report{Root: dir}.readSource("B.sol")).snippet(f)
+	if len(lines) != 1 || lines[0].Number != 4 {
+		t.Errorf("got lines %+v, want line content only", lines)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	line := `uint256 x = 0x10; require(x > 1, "a<b"); // c`
+	got := string(highlight(line, lex(line).regions, -1, -1))
+	want := `<span class="t">uint256</span> x = <span class="n">0x10</span>; <span class="k">require</span>(x &gt; <span class="n">1</span>, <span class="s">&#34;a&lt;b&#34;</span>); <span class="c">// c</span>`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
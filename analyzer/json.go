@@ -0,0 +1,301 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// Version is the version of c4udit, set at build time with
+// -ldflags "-X github.com/byterocket/c4udit/analyzer.Version=v1.2.3".
+var Version = "dev"
+
+// JSONSchemaVersion is the version of the JSON report format, see
+// docs/report.schema.json. It is incremented on incompatible changes.
+const JSONSchemaVersion = 1
+
+// jsonReport is the JSON report format.
+type jsonReport struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	Tool          jsonTool          `json:"tool"`
+	Metadata      Metadata          `json:"metadata"`
+	Root          string            `json:"root"`
+	Ruleset       jsonRuleset       `json:"ruleset"`
+	FilesAnalyzed []string          `json:"filesAnalyzed"`
+	Issues        []jsonIssue       `json:"issues"`
+	Errors        []jsonError       `json:"errors"`
+	Suppressions  []jsonSuppression `json:"suppressions"`
+	Baseline      *jsonBaseline     `json:"baseline,omitempty"`
+	Scope         string            `json:"scope,omitempty"`
+	ProjectRoot   string            `json:"projectRoot,omitempty"`
+}
+
+type jsonTool struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type jsonRuleset struct {
+	// Sources are the rule files the issues were loaded from, "built-in"
+	// for the built-in issues.
+	Sources []string `json:"sources"`
+	Issues  int      `json:"issues"`
+}
+
+type jsonIssue struct {
+	Identifier      string        `json:"identifier"`
+	Severity        string        `json:"severity"`
+	Title           string        `json:"title"`
+	Impact          string        `json:"impact"`
+	Recommendation  string        `json:"recommendation"`
+	Pattern         string        `json:"pattern,omitempty"`
+	Detector        bool          `json:"detector"`
+	Scope           string        `json:"scope"`
+	ExcludePatterns []string      `json:"excludePatterns,omitempty"`
+	Files           []string      `json:"files,omitempty"`
+	Source          string        `json:"source"`
+	Suppressed      int           `json:"suppressed"`
+	Findings        []jsonFinding `json:"findings"`
+}
+
+type jsonFinding struct {
+	File        string `json:"file"`
+	Line        int    `json:"line"`
+	EndLine     int    `json:"endLine"`
+	Column      int    `json:"column"`
+	EndColumn   int    `json:"endColumn"`
+	Match       string `json:"match"`
+	Snippet     string `json:"snippet"`
+	Function    string `json:"function,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+type jsonError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+type jsonSuppression struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Directive string `json:"directive"`
+	Count     int    `json:"count"`
+}
+
+type jsonBaseline struct {
+	Known int `json:"known"`
+	Stale int `json:"stale"`
+}
+
+// JSON returns the report in the JSON report format.
+func (r Report) JSON() ([]byte, error) {
+	out := jsonReport{
+		SchemaVersion: JSONSchemaVersion,
+		Tool:          jsonTool{Name: "c4udit", Version: Version},
+		Metadata:      r.Metadata,
+		Root:          r.Root,
+		Ruleset:       jsonRuleset{Sources: []string{}, Issues: len(r.Issues)},
+		FilesAnalyzed: r.FilesAnalyzed,
+		Issues:        []jsonIssue{},
+		Errors:        []jsonError{},
+		Suppressions:  []jsonSuppression{},
+	}
+	if out.FilesAnalyzed == nil {
+		out.FilesAnalyzed = []string{}
+	}
+
+	sources := make(map[string]bool)
+	for _, issue := range r.Issues {
+		source := issue.Source
+		if source == "" {
+			source = "built-in"
+		}
+		if !sources[source] {
+			sources[source] = true
+			out.Ruleset.Sources = append(out.Ruleset.Sources, source)
+		}
+
+		i := jsonIssue{
+			Identifier:      issue.Identifier,
+			Severity:        issue.Severity.shortName(),
+			Title:           issue.Title,
+			Impact:          issue.Impact,
+			Recommendation:  issue.Recommendation,
+			Pattern:         issue.Pattern,
+			Detector:        issue.Detector != nil,
+			Scope:           issue.Scope.String(),
+			ExcludePatterns: issue.ExcludePatterns,
+			Files:           issue.Files,
+			Source:          source,
+			Suppressed:      r.SuppressedPerIssue[issue.Identifier],
+			Findings:        []jsonFinding{},
+		}
+		for _, f := range r.FindingsPerIssue[issue.Identifier] {
+			i.Findings = append(i.Findings, jsonFinding{
+				File:        f.File,
+				Line:        f.LineNumber,
+				EndLine:     f.EndLineNumber,
+				Column:      f.Column,
+				EndColumn:   f.EndColumn,
+				Match:       f.Match,
+				Snippet:     f.LineContent,
+				Function:    f.Function,
+				Fingerprint: r.Fingerprint(f),
+			})
+		}
+		out.Issues = append(out.Issues, i)
+	}
+
+	for _, e := range r.Errors {
+		out.Errors = append(out.Errors, jsonError{Path: e.Path, Message: e.Message})
+	}
+	for _, s := range r.Suppressions {
+		out.Suppressions = append(out.Suppressions, jsonSuppression{
+			File:      s.File,
+			Line:      s.Line,
+			Directive: s.Directive,
+			Count:     s.Count,
+		})
+	}
+	if r.Baseline != nil {
+		out.Baseline = &jsonBaseline{Known: r.Baseline.Known, Stale: r.Baseline.Stale}
+	}
+	out.Scope = r.Scope
+	if r.ProjectRoot != r.Root {
+		out.ProjectRoot = r.ProjectRoot
+	}
+
+	buf := bytes.Buffer{}
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(out); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// loadedDetector stands in for the Detector of an Issue loaded from a JSON
+// report. It finds nothing.
+type loadedDetector struct{}
+
+func (loadedDetector) Detect(file *SourceFile) []Finding {
+	return nil
+}
+
+// ParseJSON reads a Report in the JSON report format. Issues implemented by
+// a Detector can be rendered but not run, their Detector finds nothing.
+func ParseJSON(data []byte) (*Report, error) {
+	in := jsonReport{}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, err
+	}
+	if in.SchemaVersion < 1 || in.SchemaVersion > JSONSchemaVersion {
+		return nil, fmt.Errorf("unsupported report schema version %d", in.SchemaVersion)
+	}
+
+	r := &Report{
+		Metadata:           in.Metadata,
+		Root:               in.Root,
+		ProjectRoot:        in.ProjectRoot,
+		Issues:             []Issue{},
+		FilesAnalyzed:      in.FilesAnalyzed,
+		FindingsPerIssue:   make(map[string][]Finding),
+		Errors:             []AnalysisError{},
+		Suppressions:       []Suppression{},
+		SuppressedPerIssue: make(map[string]int),
+	}
+	if r.ProjectRoot == "" {
+		// The fingerprints were computed relative to the root.
+		r.ProjectRoot = in.Root
+	}
+	if r.FilesAnalyzed == nil {
+		r.FilesAnalyzed = []string{}
+	}
+
+	for _, i := range in.Issues {
+		severity, err := ParseSeverity(i.Severity)
+		if err != nil {
+			return nil, fmt.Errorf("issue %s: %s", i.Identifier, err)
+		}
+		scope, err := ParseScope(i.Scope)
+		if err != nil {
+			return nil, fmt.Errorf("issue %s: %s", i.Identifier, err)
+		}
+		source := i.Source
+		if source == "built-in" {
+			source = ""
+		}
+
+		var detector Detector
+		if i.Detector {
+			detector = loadedDetector{}
+		}
+
+		r.Issues = append(r.Issues, Issue{
+			Identifier:      i.Identifier,
+			Severity:        severity,
+			Title:           i.Title,
+			Impact:          i.Impact,
+			Pattern:         i.Pattern,
+			Recommendation:  i.Recommendation,
+			Scope:           scope,
+			Detector:        detector,
+			ExcludePatterns: i.ExcludePatterns,
+			Files:           i.Files,
+			Source:          source,
+		})
+		if i.Suppressed > 0 {
+			r.SuppressedPerIssue[i.Identifier] = i.Suppressed
+		}
+
+		findings := []Finding{}
+		for _, f := range i.Findings {
+			findings = append(findings, Finding{
+				IssueIdentifier: i.Identifier,
+				File:            f.File,
+				LineNumber:      f.Line,
+				EndLineNumber:   f.EndLine,
+				Column:          f.Column,
+				EndColumn:       f.EndColumn,
+				Match:           f.Match,
+				LineContent:     f.Snippet,
+				Function:        f.Function,
+			})
+		}
+		r.FindingsPerIssue[i.Identifier] = findings
+	}
+
+	for _, e := range in.Errors {
+		r.Errors = append(r.Errors, AnalysisError{Path: e.Path, Message: e.Message})
+	}
+	for _, s := range in.Suppressions {
+		r.Suppressions = append(r.Suppressions, Suppression{
+			File:      s.File,
+			Line:      s.Line,
+			Directive: s.Directive,
+			Count:     s.Count,
+		})
+	}
+	if in.Baseline != nil {
+		r.Baseline = &BaselineStats{Known: in.Baseline.Known, Stale: in.Baseline.Stale}
+	}
+	r.Scope = in.Scope
+
+	return r, nil
+}
+
+// LoadReport reads a Report in the JSON report format from the file at
+// `path`.
+func LoadReport(path string) (*Report, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := ParseJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid report %s: %s", path, err)
+	}
+	return r, nil
+}
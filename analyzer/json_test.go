@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	report := fixtureReport(t)
+	report.Metadata = Metadata{Title: "Audit", Project: "Example"}
+	report.Baseline = &BaselineStats{Known: 1, Stale: 2}
+
+	data, err := report.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The report matches the documented schema.
+	schema, err := jsonschema.Compile(filepath.Join("..", "docs", "report.schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if err := schema.Validate(doc); err != nil {
+		t.Errorf("report does not match schema: %#v", err)
+	}
+
+	loaded, err := ParseJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := loaded.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Errorf("report changed after loading:\n%s\n%s", data, again)
+	}
+	if loaded.Markdown(RenderOptions{}) != report.Markdown(RenderOptions{}) {
+		t.Errorf("loaded report renders differently")
+	}
+
+	// Check the fields of a finding.
+	var parsed struct {
+		SchemaVersion int `json:"schemaVersion"`
+		Issues        []struct {
+			Identifier string                   `json:"identifier"`
+			Findings   []map[string]interface{} `json:"findings"`
+		} `json:"issues"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.SchemaVersion != JSONSchemaVersion {
+		t.Errorf("got schema version %d", parsed.SchemaVersion)
+	}
+	found := false
+	for _, issue := range parsed.Issues {
+		for _, f := range issue.Findings {
+			found = true
+			for _, field := range []string{"file", "line", "column", "snippet", "fingerprint"} {
+				if _, ok := f[field]; !ok {
+					t.Errorf("%s finding misses %q: %v", issue.Identifier, field, f)
+				}
+			}
+		}
+	}
+	if !found {
+		t.Errorf("report has no findings")
+	}
+}
+
+func TestParseJSONVersion(t *testing.T) {
+	if _, err := ParseJSON([]byte(`{"schemaVersion": 2}`)); err == nil {
+		t.Errorf("expected error for unsupported schema version")
+	}
+}
+
+func TestParseJSONFingerprints(t *testing.T) {
+	// The report was written on another machine, its root does not exist
+	// here.
+	root := filepath.Join(t.TempDir(), "audit", "src")
+	report := fixtureReport(t)
+	report.Root = root
+	report.ProjectRoot = filepath.Dir(root)
+
+	data, err := report.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ParseJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.ProjectRoot != report.ProjectRoot {
+		t.Errorf("got project root %q, want %q", loaded.ProjectRoot, report.ProjectRoot)
+	}
+	for _, issue := range report.Issues {
+		want := report.FindingsPerIssue[issue.Identifier]
+		for i, f := range loaded.FindingsPerIssue[issue.Identifier] {
+			if got := loaded.Fingerprint(f); got != report.Fingerprint(want[i]) {
+				t.Errorf("%s: fingerprint of loaded finding %d changed", issue.Identifier, i)
+			}
+		}
+	}
+}
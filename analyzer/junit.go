@@ -0,0 +1,102 @@
+package analyzer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Errors    []junitResult `xml:"error"`
+	Failures  []junitResult `xml:"failure"`
+}
+
+type junitResult struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",cdata"`
+}
+
+// JUnit returns the report in the JUnit XML format read by CI servers.
+// There is a test suite per severity with a test case per Issue, which
+// fails if the Issue has findings. The findings are the failure's body.
+// Paths that could not be analyzed are errors of an "Analysis" test suite.
+func (r Report) JUnit() ([]byte, error) {
+	out := junitTestSuites{Name: "c4udit"}
+
+	for _, severity := range []Severity{LOW, NC, GASOP} {
+		suite := junitTestSuite{Name: severity.String()}
+		for _, issue := range r.Issues {
+			if issue.Severity != severity {
+				continue
+			}
+
+			c := junitTestCase{
+				Name:      fmt.Sprintf("[%s] %s", issue.Identifier, issue.Title),
+				Classname: "c4udit." + severity.shortName(),
+			}
+			if findings := r.FindingsPerIssue[issue.Identifier]; len(findings) > 0 {
+				body := strings.Builder{}
+				for _, f := range findings {
+					body.WriteString(f.String())
+				}
+				message := strconv.Itoa(len(findings)) + " findings"
+				if len(findings) == 1 {
+					message = "1 finding"
+				}
+				c.Failures = append(c.Failures, junitResult{
+					Message: message,
+					Type:    issue.Identifier,
+					Body:    body.String(),
+				})
+				suite.Failures++
+			}
+			suite.Cases = append(suite.Cases, c)
+			suite.Tests++
+		}
+		if suite.Tests > 0 {
+			out.Suites = append(out.Suites, suite)
+		}
+	}
+
+	if len(r.Errors) > 0 {
+		suite := junitTestSuite{Name: "Analysis"}
+		for _, e := range r.Errors {
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      e.Path,
+				Classname: "c4udit.analysis",
+				Errors:    []junitResult{{Message: e.Message}},
+			})
+			suite.Tests++
+			suite.Errors++
+		}
+		out.Suites = append(out.Suites, suite)
+	}
+
+	for _, suite := range out.Suites {
+		out.Tests += suite.Tests
+		out.Failures += suite.Failures
+		out.Errors += suite.Errors
+	}
+	return marshalXML(out)
+}
@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestJUnitSchema(t *testing.T) {
+	data, err := fixtureReport(t, "Missing.sol").JUnit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validateXML(t, "junit-4.xsd", data)
+}
+
+func TestJUnit(t *testing.T) {
+	report := fixtureReport(t, "Missing.sol")
+	data, err := report.JUnit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed := junitTestSuites{}
+	if err := xml.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Tests != len(report.Issues)+len(report.Errors) || parsed.Errors != len(report.Errors) {
+		t.Errorf("got %d tests and %d errors", parsed.Tests, parsed.Errors)
+	}
+
+	cases := 0
+	for _, suite := range parsed.Suites {
+		for _, c := range suite.Cases {
+			cases++
+			if suite.Name == "Analysis" {
+				continue
+			}
+			id := strings.TrimPrefix(strings.SplitN(c.Name, "]", 2)[0], "[")
+			findings := report.FindingsPerIssue[id]
+			if (len(c.Failures) > 0) != (len(findings) > 0) {
+				t.Errorf("%s: got %d failures with %d findings", id, len(c.Failures), len(findings))
+				continue
+			}
+			for _, f := range findings {
+				if !strings.Contains(c.Failures[0].Body, f.String()) {
+					t.Errorf("%s: failure misses finding %q", id, f.String())
+				}
+			}
+		}
+	}
+	if cases != parsed.Tests {
+		t.Errorf("got %d test cases, want %d", cases, parsed.Tests)
+	}
+}
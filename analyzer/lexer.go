@@ -0,0 +1,53 @@
+package analyzer
+
+import "github.com/byterocket/c4udit/analyzer/solidity"
+
+// lexedSource is a Solidity source file in which every byte is tagged as
+// code, comment or string literal content, see solidity.Regions.
+type lexedSource struct {
+	raw     string
+	regions []solidity.Region
+}
+
+// lex tags the bytes of the Solidity source `src`.
+func lex(src string) *lexedSource {
+	return &lexedSource{
+		raw:     src,
+		regions: solidity.Regions(src),
+	}
+}
+
+// view returns the source with every byte not in one of the `keep` regions
+// replaced by a space. Newlines are always kept so that line and column
+// positions in the view match the raw source.
+func (l *lexedSource) view(keep ...solidity.Region) string {
+	buf := []byte(l.raw)
+	for i := range buf {
+		if buf[i] == '\n' {
+			continue
+		}
+
+		kept := false
+		for _, r := range keep {
+			if l.regions[i] == r {
+				kept = true
+				break
+			}
+		}
+		if !kept {
+			buf[i] = ' '
+		}
+	}
+
+	return string(buf)
+}
+
+// overlaps reports whether any byte in [start, end) belongs to region `r`.
+func (l *lexedSource) overlaps(start, end int, r solidity.Region) bool {
+	for i := start; i < end && i < len(l.regions); i++ {
+		if l.regions[i] == r {
+			return true
+		}
+	}
+	return false
+}
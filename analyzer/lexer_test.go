@@ -0,0 +1,42 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/byterocket/c4udit/analyzer/solidity"
+)
+
+func TestLexViews(t *testing.T) {
+	src := strings.Join([]string{
+		`uint a = 1; // i++ TODO`,
+		`/* ecrecover(`,
+		`   "not a string" */ string s = "i++ \" uint";`,
+		`bytes h = hex"00ff";`,
+	}, "\n")
+
+	source := lex(src)
+
+	code := strings.Split(source.view(solidity.CODE), "\n")
+	if strings.Contains(code[0], "i++") || strings.Contains(code[1], "ecrecover") {
+		t.Errorf("comment leaked into code view: %q", code)
+	}
+	if strings.Contains(code[2], "uint") || strings.Contains(code[3], "00ff") {
+		t.Errorf("literal leaked into code view: %q", code)
+	}
+	if !strings.Contains(code[2], `string s = "`) || !strings.Contains(code[3], `hex"`) {
+		t.Errorf("code missing from code view: %q", code)
+	}
+
+	comments := strings.Split(source.view(solidity.COMMENT), "\n")
+	if !strings.Contains(comments[0], "TODO") || !strings.Contains(comments[1], "ecrecover(") {
+		t.Errorf("comment missing from comment view: %q", comments)
+	}
+	if !strings.Contains(comments[2], `"not a string"`) || strings.Contains(comments[2], "uint") {
+		t.Errorf("unexpected comment view: %q", comments)
+	}
+
+	if len(source.view(solidity.LITERAL)) != len(src) {
+		t.Errorf("view changed source length")
+	}
+}
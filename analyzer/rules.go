@@ -0,0 +1,103 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ruleFile is the format of a rule file:
+//
+//	rules:
+//	  - id: X-01
+//	    severity: low
+//	    title: Use of `tx.origin`
+//	    impact: ...
+//	    pattern: \btx\.origin\b
+//	    recommendation: ...
+//	    scope: code
+//	    excludePatterns: ['require\(tx\.origin == msg\.sender']
+//	    files: [src/]
+//
+// Rule files are YAML, or JSON if the file name ends in ".json".
+type ruleFile struct {
+	Rules []rule `json:"rules" yaml:"rules"`
+}
+
+type rule struct {
+	ID              string   `json:"id" yaml:"id"`
+	Severity        string   `json:"severity" yaml:"severity"`
+	Title           string   `json:"title" yaml:"title"`
+	Impact          string   `json:"impact" yaml:"impact"`
+	Pattern         string   `json:"pattern" yaml:"pattern"`
+	Recommendation  string   `json:"recommendation" yaml:"recommendation"`
+	Scope           string   `json:"scope" yaml:"scope"`
+	ExcludePatterns []string `json:"excludePatterns" yaml:"excludePatterns"`
+	Files           []string `json:"files" yaml:"files"`
+}
+
+// LoadRules reads the Issues defined in the rule file at `path`. Patterns
+// are not validated, see Compile.
+func LoadRules(path string) ([]Issue, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := ruleFile{}
+	if filepath.Ext(path) == ".json" {
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&file)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid rules %s: %s", path, err)
+	}
+
+	issues := []Issue{}
+	problems := []string{}
+	for i, r := range file.Rules {
+		name := r.ID
+		if name == "" {
+			name = fmt.Sprintf("rule #%d", i+1)
+		}
+
+		severity, err := ParseSeverity(r.Severity)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s in %s", name, err, path))
+		}
+		scope := CODE
+		if r.Scope != "" {
+			scope, err = ParseScope(r.Scope)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %s in %s", name, err, path))
+			}
+		}
+
+		issues = append(issues, Issue{
+			Identifier:      r.ID,
+			Severity:        severity,
+			Title:           r.Title,
+			Impact:          r.Impact,
+			Pattern:         r.Pattern,
+			Recommendation:  r.Recommendation,
+			Scope:           scope,
+			ExcludePatterns: r.ExcludePatterns,
+			Files:           r.Files,
+			Source:          path,
+		})
+	}
+
+	if len(problems) > 0 {
+		return nil, &RulesetError{problems}
+	}
+	return issues, nil
+}
@@ -0,0 +1,120 @@
+package analyzer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const ruleSource = `contract A {
+    function f() public {
+        require(tx.origin == msg.sender);
+        address a = tx.origin;
+    }
+}
+`
+
+func TestLoadRules(t *testing.T) {
+	dir := t.TempDir()
+	yamlRules := filepath.Join(dir, "team.yaml")
+	err := ioutil.WriteFile(yamlRules, []byte(`rules:
+  - id: X-01
+    severity: low
+    title: Use of tx.origin
+    pattern: \btx\.origin\b
+    excludePatterns: ['tx\.origin == msg\.sender']
+    files: [src/]
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonRules := filepath.Join(dir, "team.json")
+	err = ioutil.WriteFile(jsonRules, []byte(`{"rules": [
+		{"id": "G-01", "severity": "gas", "title": "Collides", "pattern": "x"}
+	]}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := LoadRules(yamlRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].Severity != LOW || issues[0].Scope != CODE || issues[0].Source != yamlRules {
+		t.Fatalf("unexpected issues: %+v", issues)
+	}
+
+	// Rules are only run on files matching their globs and findings matching
+	// an exclude pattern are dropped.
+	for _, name := range []string{"src/A.sol", "test/A.sol"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(ruleSource), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	report, err := Run(issues, []string{filepath.Join(dir, "src"), filepath.Join(dir, "test")}, Options{Root: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := report.FindingsPerIssue["X-01"]
+	if len(findings) != 1 || findings[0].File != "src/A.sol" || findings[0].LineNumber != 4 {
+		t.Errorf("unexpected findings: %+v", findings)
+	}
+
+	// Rules colliding with built-in issues are rejected.
+	collisions, err := LoadRules(jsonRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Compile(append(AllIssues(), collisions...))
+	rulesetErr, ok := err.(*RulesetError)
+	if !ok {
+		t.Fatalf("got error %v, want *RulesetError", err)
+	}
+	want := []string{"G-01: duplicate identifier, defined built-in and in " + jsonRules}
+	if !reflect.DeepEqual(rulesetErr.Problems, want) {
+		t.Errorf("got problems %q, want %q", rulesetErr.Problems, want)
+	}
+}
+
+func TestLoadRulesErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "team.yaml")
+	err := ioutil.WriteFile(path, []byte(`rules:
+  - id: X-01
+    severity: critical
+    pattern: x
+  - id: X-02
+    severity: nc
+    scope: everywhere
+    pattern: x
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = LoadRules(path)
+	rulesetErr, ok := err.(*RulesetError)
+	if !ok {
+		t.Fatalf("got error %v, want *RulesetError", err)
+	}
+	want := []string{
+		`X-01: unknown severity "critical" in ` + path,
+		`X-02: unknown scope "everywhere" in ` + path,
+	}
+	if !reflect.DeepEqual(rulesetErr.Problems, want) {
+		t.Errorf("got problems %q, want %q", rulesetErr.Problems, want)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("rules:\n  - id: X-01\n    patern: x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRules(path); err == nil {
+		t.Errorf("expected error for unknown field")
+	}
+}
@@ -0,0 +1,107 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Ruleset is a validated list of Issues with their patterns compiled, ready
+// to be run against source files.
+type Ruleset struct {
+	Issues []Issue
+	// Compiled patterns, nil for Issues with a Detector.
+	patterns []*regexp.Regexp
+	// Compiled exclude patterns per Issue.
+	excludes [][]*regexp.Regexp
+}
+
+// RulesetError lists the problems found while compiling a Ruleset.
+type RulesetError struct {
+	Problems []string
+}
+
+func (e *RulesetError) Error() string {
+	return "invalid rules:\n- " + strings.Join(e.Problems, "\n- ") + "\n"
+}
+
+// Compile validates `issues` and compiles their patterns into a Ruleset.
+// Every Issue needs a unique Identifier and either a Detector or a valid
+// pattern. All problems are reported at once as a *RulesetError.
+func Compile(issues []Issue) (*Ruleset, error) {
+	ruleset := &Ruleset{
+		Issues:   issues,
+		patterns: make([]*regexp.Regexp, len(issues)),
+		excludes: make([][]*regexp.Regexp, len(issues)),
+	}
+
+	problems := []string{}
+	seen := make(map[string]Issue)
+	for i, issue := range issues {
+		name := issue.Identifier
+		if name == "" {
+			name = fmt.Sprintf("issue #%d", i+1)
+			problems = append(problems, name+": missing identifier")
+		} else if other, ok := seen[name]; ok {
+			problem := name + ": duplicate identifier"
+			if other.Source != "" || issue.Source != "" {
+				problem += fmt.Sprintf(", defined %s and %s", other.origin(), issue.origin())
+			}
+			problems = append(problems, problem)
+		}
+		seen[name] = issue
+
+		for _, exclude := range issue.ExcludePatterns {
+			pattern, err := regexp.Compile(exclude)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: invalid exclude pattern: %s", name, err))
+				continue
+			}
+			ruleset.excludes[i] = append(ruleset.excludes[i], pattern)
+		}
+
+		if issue.Detector != nil {
+			continue
+		}
+		if issue.Pattern == "" {
+			problems = append(problems, name+": missing pattern")
+			continue
+		}
+
+		pattern, err := regexp.Compile(issue.Pattern)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: invalid pattern: %s", name, err))
+			continue
+		}
+		ruleset.patterns[i] = pattern
+	}
+
+	if len(problems) > 0 {
+		return nil, &RulesetError{problems}
+	}
+	return ruleset, nil
+}
+
+// origin describes where the Issue was defined.
+func (issue Issue) origin() string {
+	if issue.Source == "" {
+		return "built-in"
+	}
+	return "in " + issue.Source
+}
+
+// appliesTo reports whether the Issue checks the file at relative path `name`.
+func (issue Issue) appliesTo(name string) bool {
+	return len(issue.Files) == 0 || matchAny(issue.Files, name)
+}
+
+// excluded reports whether the exclude patterns of the `i`th Issue drop
+// finding `f`.
+func (r *Ruleset) excluded(i int, f Finding) bool {
+	for _, pattern := range r.excludes[i] {
+		if pattern.MatchString(f.LineContent) {
+			return true
+		}
+	}
+	return false
+}
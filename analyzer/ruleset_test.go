@@ -0,0 +1,34 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	if _, err := Compile(AllIssues()); err != nil {
+		t.Fatalf("built-in issues do not compile: %v", err)
+	}
+
+	_, err := Compile([]Issue{
+		{Identifier: "X-01", Pattern: `ok`},
+		{Identifier: "X-01", Pattern: `ok`},
+		{Identifier: "X-02", Pattern: `(unclosed`},
+		{Identifier: "X-03"},
+		{Pattern: `ok`},
+	})
+	rulesetErr, ok := err.(*RulesetError)
+	if !ok {
+		t.Fatalf("got error %v, want *RulesetError", err)
+	}
+
+	want := []string{
+		"X-01: duplicate identifier",
+		"X-02: invalid pattern: error parsing regexp: missing closing ): `(unclosed`",
+		"X-03: missing pattern",
+		"issue #5: missing identifier",
+	}
+	if !reflect.DeepEqual(rulesetErr.Problems, want) {
+		t.Errorf("got problems %q, want %q", rulesetErr.Problems, want)
+	}
+}
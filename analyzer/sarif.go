@@ -0,0 +1,279 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// sarifSchema is the URI of the SARIF 2.1.0 JSON schema.
+const sarifSchema = "https://docs.oasis-open.org/sarif/sarif/v2.1.0/errata01/os/schemas/sarif-schema-2.1.0.json"
+
+// sarifRootBase is the base id of URIs relative to the project root, which
+// code scanning resolves against the repository root.
+const sarifRootBase = "%SRCROOT%"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	Invocations        []sarifInvocation                `json:"invocations"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	ColumnKind         string                           `json:"columnKind"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string                     `json:"name"`
+	Version        string                     `json:"version"`
+	InformationURI string                     `json:"informationUri"`
+	Rules          []sarifReportingDescriptor `json:"rules"`
+}
+
+type sarifReportingDescriptor struct {
+	ID                   string              `json:"id"`
+	ShortDescription     sarifMessage        `json:"shortDescription"`
+	FullDescription      *sarifMessage       `json:"fullDescription,omitempty"`
+	Help                 sarifMessage        `json:"help"`
+	DefaultConfiguration sarifConfiguration  `json:"defaultConfiguration"`
+	Properties           sarifRuleProperties `json:"properties"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifRuleProperties struct {
+	Tags []string `json:"tags"`
+}
+
+type sarifMessage struct {
+	Text     string `json:"text"`
+	Markdown string `json:"markdown,omitempty"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications"`
+}
+
+type sarifNotification struct {
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+	ContextRegion    *sarifRegion          `json:"contextRegion,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int           `json:"startLine"`
+	EndLine     int           `json:"endLine,omitempty"`
+	StartColumn int           `json:"startColumn,omitempty"`
+	EndColumn   int           `json:"endColumn,omitempty"`
+	Snippet     *sarifMessage `json:"snippet,omitempty"`
+}
+
+// sarifLevel returns the SARIF level of findings of severity `s`.
+func (s Severity) sarifLevel() string {
+	if s == LOW {
+		return "warning"
+	}
+	return "note"
+}
+
+// SARIF returns the report as a SARIF 2.1.0 log with a single run. Every
+// Issue is a rule of the run, every Finding a result.
+func (r Report) SARIF() ([]byte, error) {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "c4udit",
+			Version:        Version,
+			InformationURI: "https://github.com/byterocket/c4udit",
+			Rules:          []sarifReportingDescriptor{},
+		}},
+		Invocations: []sarifInvocation{{
+			ExecutionSuccessful:        true,
+			ToolExecutionNotifications: []sarifNotification{},
+		}},
+		ColumnKind: "unicodeCodePoints",
+		Results:    []sarifResult{},
+	}
+	if base := r.sarifBase(); base != "" {
+		run.OriginalURIBaseIDs = map[string]sarifArtifactLocation{
+			sarifRootBase: {URI: fileURI(base) + "/"},
+		}
+	}
+
+	for i, issue := range r.Issues {
+		rule := sarifReportingDescriptor{
+			ID:                   issue.Identifier,
+			ShortDescription:     sarifMessage{Text: issue.Title},
+			Help:                 issue.sarifHelp(),
+			DefaultConfiguration: sarifConfiguration{Level: issue.Severity.sarifLevel()},
+			Properties:           sarifRuleProperties{Tags: []string{issue.Severity.shortName()}},
+		}
+		if issue.Impact != "" {
+			rule.FullDescription = &sarifMessage{Text: issue.Impact}
+		}
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+
+		for _, f := range r.FindingsPerIssue[issue.Identifier] {
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    issue.Identifier,
+				RuleIndex: i,
+				Level:     issue.Severity.sarifLevel(),
+				Message:   sarifMessage{Text: issue.Title},
+				Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: r.sarifArtifact(f.File),
+					Region:           f.sarifRegion(),
+					ContextRegion:    f.sarifContextRegion(),
+				}}},
+				PartialFingerprints: map[string]string{
+					"c4udit/v1": r.Fingerprint(f),
+				},
+			})
+		}
+	}
+
+	for _, e := range r.Errors {
+		run.Invocations[0].ToolExecutionNotifications = append(run.Invocations[0].ToolExecutionNotifications, sarifNotification{
+			Level:   "warning",
+			Message: sarifMessage{Text: e.Message},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: r.sarifArtifact(e.Path),
+			}}},
+		})
+	}
+
+	buf := bytes.Buffer{}
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// sarifHelp returns the help text of the Issue: its impact and
+// recommendation.
+func (issue Issue) sarifHelp() sarifMessage {
+	text := []string{}
+	markdown := []string{}
+	if issue.Impact != "" {
+		text = append(text, "Impact: "+issue.Impact)
+		markdown = append(markdown, "#### Impact\n"+issue.Impact)
+	}
+	if issue.Recommendation != "" {
+		text = append(text, "Recommendation: "+issue.Recommendation)
+		markdown = append(markdown, "#### Recommendation\n"+issue.Recommendation)
+	}
+	if len(text) == 0 {
+		return sarifMessage{Text: issue.Title}
+	}
+	return sarifMessage{
+		Text:     strings.Join(text, "\n\n"),
+		Markdown: strings.Join(markdown, "\n\n"),
+	}
+}
+
+// sarifBase returns the directory that artifact URIs are relative to: the
+// project root, e.g. the git repository, or the report's root.
+func (r Report) sarifBase() string {
+	if r.ProjectRoot != "" {
+		return r.ProjectRoot
+	}
+	return r.Root
+}
+
+// sarifArtifact returns the location of the file at report path `path`,
+// relative to the project root.
+func (r Report) sarifArtifact(path string) sarifArtifactLocation {
+	path = r.projectPath(path)
+	if filepath.IsAbs(filepath.FromSlash(path)) || r.sarifBase() == "" {
+		return sarifArtifactLocation{URI: fileURI(path)}
+	}
+	return sarifArtifactLocation{
+		URI:       (&url.URL{Path: path}).EscapedPath(),
+		URIBaseID: sarifRootBase,
+	}
+}
+
+// sarifRegion returns the region of the finding's match, or its lines if
+// the columns are unknown.
+func (f Finding) sarifRegion() *sarifRegion {
+	if f.LineNumber < 1 {
+		return nil
+	}
+	region := &sarifRegion{
+		StartLine: f.LineNumber,
+		EndLine:   f.EndLineNumber,
+	}
+	if f.Column > 0 && f.EndColumn > 0 {
+		region.StartColumn = f.Column
+		region.EndColumn = f.EndColumn
+		if f.Match != "" {
+			region.Snippet = &sarifMessage{Text: f.Match}
+		}
+	}
+	return region
+}
+
+// sarifContextRegion returns the region of the finding's lines.
+func (f Finding) sarifContextRegion() *sarifRegion {
+	if f.LineNumber < 1 || f.LineContent == "" {
+		return nil
+	}
+	return &sarifRegion{
+		StartLine: f.LineNumber,
+		EndLine:   f.EndLineNumber,
+		Snippet:   &sarifMessage{Text: f.LineContent},
+	}
+}
+
+// fileURI returns the file URI of absolute path `path`.
+func fileURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// Windows paths, e.g. C:/src.
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
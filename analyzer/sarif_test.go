@@ -0,0 +1,118 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestSARIF(t *testing.T) {
+	report := fixtureReport(t, "Missing.sol")
+	if len(report.Errors) == 0 {
+		t.Fatal("expected an error for the missing file")
+	}
+	// The analyzed directory is a subdirectory of the repository.
+	report.ProjectRoot = filepath.Dir(report.Root)
+	uri := filepath.Base(report.Root) + "/A.sol"
+
+	data, err := report.SARIF()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The log matches the SARIF 2.1.0 schema.
+	schema, err := jsonschema.Compile(filepath.Join("testdata", "sarif-schema-2.1.0.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if err := schema.Validate(doc); err != nil {
+		t.Errorf("log does not match schema: %#v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatal(err)
+	}
+	if len(log.Runs) != 1 {
+		t.Fatalf("got %d runs", len(log.Runs))
+	}
+	run := log.Runs[0]
+
+	if len(run.Tool.Driver.Rules) != len(report.Issues) {
+		t.Errorf("got %d rules for %d issues", len(run.Tool.Driver.Rules), len(report.Issues))
+	}
+	for i, rule := range run.Tool.Driver.Rules {
+		issue := report.Issues[i]
+		if rule.ID != issue.Identifier || rule.ShortDescription.Text != issue.Title {
+			t.Errorf("rule %d: got %s %q", i, rule.ID, rule.ShortDescription.Text)
+		}
+		if rule.DefaultConfiguration.Level != issue.Severity.sarifLevel() {
+			t.Errorf("%s: got level %s", rule.ID, rule.DefaultConfiguration.Level)
+		}
+		if rule.Help.Text == "" {
+			t.Errorf("%s: missing help", rule.ID)
+		}
+	}
+
+	findings := 0
+	for _, issue := range report.Issues {
+		findings += len(report.FindingsPerIssue[issue.Identifier])
+	}
+	if len(run.Results) != findings || findings == 0 {
+		t.Errorf("got %d results for %d findings", len(run.Results), findings)
+	}
+	for _, result := range run.Results {
+		if run.Tool.Driver.Rules[result.RuleIndex].ID != result.RuleID {
+			t.Errorf("%s: wrong rule index %d", result.RuleID, result.RuleIndex)
+		}
+		location := result.Locations[0].PhysicalLocation
+		if location.ArtifactLocation.URI != uri || location.ArtifactLocation.URIBaseID != sarifRootBase {
+			t.Errorf("%s: got location %+v", result.RuleID, location.ArtifactLocation)
+		}
+		if location.Region == nil || location.Region.StartLine < 1 {
+			t.Errorf("%s: missing region", result.RuleID)
+		}
+		if result.PartialFingerprints["c4udit/v1"] == "" {
+			t.Errorf("%s: missing fingerprint", result.RuleID)
+		}
+	}
+
+	notifications := run.Invocations[0].ToolExecutionNotifications
+	if len(notifications) != len(report.Errors) {
+		t.Errorf("got %d notifications for %d errors", len(notifications), len(report.Errors))
+	}
+}
+
+func TestSARIFLevel(t *testing.T) {
+	tests := []struct {
+		severity Severity
+		want     string
+	}{
+		{LOW, "warning"},
+		{NC, "note"},
+		{GASOP, "note"},
+	}
+	for _, test := range tests {
+		if got := test.severity.sarifLevel(); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.severity.shortName(), got, test.want)
+		}
+	}
+}
+
+func TestFileURI(t *testing.T) {
+	tests := map[string]string{
+		"/src/My Contracts": "file:///src/My%20Contracts",
+		"C:/src":            "file:///C:/src",
+	}
+	for path, want := range tests {
+		if got := fileURI(path); got != want {
+			t.Errorf("fileURI(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
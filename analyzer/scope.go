@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	headingPattern  = regexp.MustCompile(`^#+\s*(.*)$`)
+	linkPattern     = regexp.MustCompile(`\]\(([^)\s]+\.sol)\)`)
+	solidityPattern = regexp.MustCompile(`[\w./@-]+\.sol\b`)
+)
+
+// LoadScope returns the files listed in the scope file at `path`, relative
+// to the directory of the scope file. Markdown files, e.g. a contest's
+// README.md, list the files in tables under a heading containing "scope".
+// Other files, e.g. scope.txt, list one file per line.
+func LoadScope(path string) ([]string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var listed []string
+	if strings.EqualFold(filepath.Ext(path), ".md") {
+		listed = parseScopeTables(string(content))
+	} else {
+		listed = parseScopeList(string(content))
+	}
+
+	dir := filepath.Dir(path)
+	files := []string{}
+	for _, file := range listed {
+		files = append(files, filepath.Join(dir, filepath.FromSlash(file)))
+	}
+	return files, nil
+}
+
+// parseScopeList returns the files listed one per line, skipping empty lines
+// and comments.
+func parseScopeList(content string) []string {
+	files := []string{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		files = append(files, line)
+	}
+	return files
+}
+
+// parseScopeTables returns the Solidity files in the first column of the
+// markdown tables in scope sections. Sections about files out of scope are
+// skipped.
+func parseScopeTables(content string) []string {
+	files := []string{}
+	inScope := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			heading := strings.ToLower(m[1])
+			inScope = strings.Contains(heading, "scope") && !strings.Contains(heading, "out of scope")
+			continue
+		}
+		if !inScope || !strings.HasPrefix(line, "|") {
+			continue
+		}
+
+		cells := strings.Split(strings.Trim(line, "|"), "|")
+		if file := scopeTableFile(cells[0]); file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// scopeTableFile returns the Solidity file in a scope table cell, e.g.
+// "[src/A.sol](https://github.com/org/repo/blob/main/src/A.sol)", or an
+// empty string.
+func scopeTableFile(cell string) string {
+	file := ""
+	if m := linkPattern.FindStringSubmatch(cell); m != nil {
+		file = m[1]
+	} else if m := solidityPattern.FindString(cell); m != "" {
+		file = m
+	} else {
+		return ""
+	}
+
+	// Links to the repository point to files in the tree of a branch.
+	if strings.Contains(file, "://") {
+		parts := strings.SplitN(file, "/blob/", 2)
+		if len(parts) != 2 {
+			return ""
+		}
+		ref := strings.SplitN(parts[1], "/", 2)
+		if len(ref) != 2 {
+			return ""
+		}
+		file = ref[1]
+	}
+	return strings.TrimPrefix(file, "./")
+}
+
+// MissingScopeFiles returns the Solidity files of `scope` that do not exist.
+func MissingScopeFiles(scope []string) []string {
+	missing := []string{}
+	for _, file := range scope {
+		if !strings.HasSuffix(file, ".sol") {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			missing = append(missing, file)
+		}
+	}
+	return missing
+}
+
+// scopedFiles returns the files of `scope` in `paths`. Files of the scope
+// that do not exist are skipped, see MissingScopeFiles.
+func scopedFiles(paths []string, scope []string) []string {
+	roots := []string{}
+	for _, path := range paths {
+		if abs, err := filepath.Abs(path); err == nil {
+			roots = append(roots, abs)
+		}
+	}
+
+	files := []string{}
+	seen := make(map[string]bool)
+	for _, file := range scope {
+		abs, err := filepath.Abs(file)
+		if err != nil || seen[abs] || !strings.HasSuffix(abs, ".sol") {
+			continue
+		}
+		seen[abs] = true
+
+		if _, err := os.Stat(abs); err != nil {
+			continue
+		}
+
+		for _, root := range roots {
+			if abs == root || strings.HasPrefix(abs, root+string(filepath.Separator)) {
+				files = append(files, file)
+				break
+			}
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return filepath.ToSlash(files[i]) < filepath.ToSlash(files[j])
+	})
+	return files
+}
@@ -0,0 +1,109 @@
+package analyzer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const contestReadme = `# Contest
+
+## Scope
+
+| Contract | SLOC | Purpose |
+| --- | --- | --- |
+| [src/A.sol](https://github.com/code-423n4/2023-01-x/blob/main/src/A.sol) | 10 | Main |
+| ` + "`src/lib/B.sol`" + ` | 5 | Library |
+| [./src/Gone.sol](src/Gone.sol) | 1 | Missing |
+
+## Out of scope
+
+| File |
+| --- |
+| src/AMock.sol |
+`
+
+func TestParseScopeTables(t *testing.T) {
+	got := parseScopeTables(contestReadme)
+	want := []string{"src/A.sol", "src/lib/B.sol", "src/Gone.sol"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestRunScope(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range []string{"src/A.sol", "src/AMock.sol", "src/lib/B.sol"} {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte("contract A {}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	readme := filepath.Join(dir, "README.md")
+	if err := ioutil.WriteFile(readme, []byte(contestReadme), 0644); err != nil {
+		t.Fatal(err)
+	}
+	scopeTxt := filepath.Join(dir, "scope.txt")
+	if err := ioutil.WriteFile(scopeTxt, []byte("# in scope\n./src/AMock.sol\n\nsrc/lib/B.sol\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		scopeFile string
+		paths     []string
+		want      []string
+		errors    []AnalysisError
+	}{
+		{
+			// Missing files of the scope are no analysis errors.
+			readme,
+			[]string{dir},
+			[]string{"src/A.sol", "src/lib/B.sol"},
+			[]AnalysisError{},
+		},
+		{
+			scopeTxt,
+			[]string{dir},
+			[]string{"src/AMock.sol", "src/lib/B.sol"},
+			[]AnalysisError{},
+		},
+		{
+			// Only the files of the scope in the given paths are analyzed.
+			scopeTxt,
+			[]string{filepath.Join(dir, "src", "lib")},
+			[]string{"src/lib/B.sol"},
+			[]AnalysisError{},
+		},
+	}
+
+	for _, test := range tests {
+		scope, err := LoadScope(test.scopeFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		report, err := Run(AllIssues(), test.paths, Options{Root: dir, Scope: scope})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(report.FilesAnalyzed, test.want) {
+			t.Errorf("%s: got files %v, want %v", test.scopeFile, report.FilesAnalyzed, test.want)
+		}
+		if !reflect.DeepEqual(report.Errors, test.errors) {
+			t.Errorf("%s: got errors %v, want %v", test.scopeFile, report.Errors, test.errors)
+		}
+	}
+
+	scope, err := LoadScope(readme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "src", "Gone.sol")}
+	if got := MissingScopeFiles(scope); !reflect.DeepEqual(got, want) {
+		t.Errorf("got missing files %v, want %v", got, want)
+	}
+}
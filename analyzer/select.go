@@ -0,0 +1,75 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Selection selects Issues by identifier and severity. Identifiers may
+// contain wildcards, e.g. "G-*". Empty fields select all Issues.
+type Selection struct {
+	// Only selects the Issues matching any of the identifiers.
+	Only []string
+	// Exclude drops the Issues matching any of the identifiers.
+	Exclude []string
+	// Severities selects the Issues with any of the severities.
+	Severities []Severity
+}
+
+// SelectIssues returns the `issues` chosen by `sel`, in their original
+// order. Identifiers matching no Issue are an error.
+func SelectIssues(issues []Issue, sel Selection) ([]Issue, error) {
+	for _, pattern := range append(append([]string{}, sel.Only...), sel.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid issue pattern %q", pattern)
+		}
+		if !matchesAnyIssue(issues, pattern) {
+			return nil, fmt.Errorf("no issue matches %q", pattern)
+		}
+	}
+
+	selected := []Issue{}
+	for _, issue := range issues {
+		if len(sel.Only) > 0 && !matchIdentifier(sel.Only, issue.Identifier) {
+			continue
+		}
+		if matchIdentifier(sel.Exclude, issue.Identifier) {
+			continue
+		}
+		if len(sel.Severities) > 0 && !containsSeverity(sel.Severities, issue.Severity) {
+			continue
+		}
+		selected = append(selected, issue)
+	}
+	return selected, nil
+}
+
+func matchesAnyIssue(issues []Issue, pattern string) bool {
+	for _, issue := range issues {
+		if matchIdentifier([]string{pattern}, issue.Identifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchIdentifier reports whether identifier `id` matches any of the
+// case-insensitive wildcard `patterns`.
+func matchIdentifier(patterns []string, id string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToUpper(pattern), strings.ToUpper(id)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func containsSeverity(list []Severity, s Severity) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSelectIssues(t *testing.T) {
+	issues := []Issue{
+		{Identifier: "G-01", Severity: GASOP},
+		{Identifier: "G-02", Severity: GASOP},
+		{Identifier: "L-04", Severity: LOW},
+		{Identifier: "N-02", Severity: NC},
+	}
+
+	tests := []struct {
+		sel  Selection
+		want []string
+	}{
+		{Selection{}, []string{"G-01", "G-02", "L-04", "N-02"}},
+		{Selection{Only: []string{"G-01", "l-04"}}, []string{"G-01", "L-04"}},
+		{Selection{Only: []string{"G-*"}, Exclude: []string{"G-02"}}, []string{"G-01"}},
+		{Selection{Exclude: []string{"N-02"}}, []string{"G-01", "G-02", "L-04"}},
+		{Selection{Severities: []Severity{GASOP, LOW}}, []string{"G-01", "G-02", "L-04"}},
+		{Selection{Only: []string{"G-01", "N-02"}, Severities: []Severity{NC}}, []string{"N-02"}},
+	}
+
+	for _, test := range tests {
+		selected, err := SelectIssues(issues, test.sel)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, issue := range selected {
+			got = append(got, issue.Identifier)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("SelectIssues(%+v) = %v, want %v", test.sel, got, test.want)
+		}
+	}
+
+	if _, err := SelectIssues(issues, Selection{Exclude: []string{"X-*"}}); err == nil {
+		t.Errorf("expected error for identifier matching no issue")
+	}
+}
+
+func TestMarkdownOnlySelectedSeverities(t *testing.T) {
+	report := Report{
+		Issues: []Issue{{Identifier: "G-01", Severity: GASOP, Title: "Gas"}},
+		FindingsPerIssue: map[string][]Finding{
+			"G-01": {{File: "A.sol", LineNumber: 1, LineContent: "x"}},
+		},
+	}
+
+	markdown := report.Markdown(RenderOptions{ToC: true})
+	for _, section := range []string{"## QA Issues found", "## Low Findings", "## Non-Critical Findings"} {
+		if strings.Contains(markdown, section) {
+			t.Errorf("report of gas issues contains %q", section)
+		}
+	}
+	if !strings.Contains(markdown, "## Gas Findings") {
+		t.Errorf("report of gas issues misses gas section")
+	}
+}
@@ -0,0 +1,583 @@
+package solidity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type parser struct {
+	src    string
+	tokens []Token
+	pos    int
+	// Byte offsets of line starts.
+	lines []int
+	// err is the first error found.
+	err error
+}
+
+// Parse parses the Solidity source `src`. Unrecognized or invalid code is
+// skipped. If the source ends inside a contract or function, the partially
+// parsed unit is returned with an error.
+func Parse(src string) (*SourceUnit, error) {
+	p := &parser{
+		src:    src,
+		tokens: Tokenize(src),
+		lines:  []int{0},
+	}
+	for i := 0; i < len(src); i++ {
+		if src[i] == '\n' {
+			p.lines = append(p.lines, i+1)
+		}
+	}
+
+	unit := &SourceUnit{
+		Pragmas:   []Pragma{},
+		Imports:   []Import{},
+		Contracts: []*Contract{},
+		Functions: []*Function{},
+	}
+
+	for !p.eof() {
+		switch p.peek().Text {
+		case "pragma":
+			unit.Pragmas = append(unit.Pragmas, p.parsePragma())
+		case "import":
+			unit.Imports = append(unit.Imports, p.parseImport())
+		case "abstract", "contract", "interface", "library":
+			unit.Contracts = append(unit.Contracts, p.parseContract())
+		case "function":
+			unit.Functions = append(unit.Functions, p.parseFunction(FUNCTION))
+		default:
+			p.skipDefinition()
+		}
+	}
+
+	return unit, p.err
+}
+
+func (p *parser) parsePragma() Pragma {
+	start := p.pos
+	p.next()
+
+	pragma := Pragma{}
+	if !p.eof() && p.peek().Text != ";" {
+		name := p.next()
+		pragma.Name = name.Text
+
+		end := p.pos
+		for !p.eof() && p.peek().Text != ";" {
+			p.next()
+			end = p.pos
+		}
+		if end > start+2 {
+			pragma.Value = strings.TrimSpace(p.src[name.End:p.tokens[end-1].End])
+		}
+	}
+	p.accept(";")
+
+	pragma.Range = p.rangeFrom(start)
+	return pragma
+}
+
+func (p *parser) parseImport() Import {
+	start := p.pos
+	p.next()
+
+	imp := Import{}
+	for !p.eof() && p.peek().Text != ";" {
+		t := p.next()
+		if t.Kind == STRING && imp.Path == "" {
+			imp.Path = t.Value()
+		}
+	}
+	p.accept(";")
+
+	imp.Range = p.rangeFrom(start)
+	return imp
+}
+
+func (p *parser) parseContract() *Contract {
+	start := p.pos
+	contract := &Contract{
+		Bases:          []string{},
+		StateVariables: []*StateVariable{},
+		Functions:      []*Function{},
+	}
+
+	if p.accept("abstract") {
+		contract.Abstract = true
+	}
+	if p.eof() {
+		p.unexpectedEOF()
+		contract.Range = p.rangeFrom(start)
+		return contract
+	}
+	switch p.next().Text {
+	case "interface":
+		contract.Kind = INTERFACE
+	case "library":
+		contract.Kind = LIBRARY
+	}
+	if !p.eof() && p.peek().Kind == IDENTIFIER {
+		contract.Name = p.next().Text
+	}
+
+	if p.accept("is") {
+		for !p.eof() && p.peek().Text != "{" {
+			t := p.next()
+			switch {
+			case t.Text == "(":
+				p.pos = p.skipBalanced(p.pos-1) + 1
+			case t.Kind == IDENTIFIER:
+				// Qualified names such as `Lib.Base` are one base.
+				if len(contract.Bases) > 0 && p.tokens[p.pos-2].Text == "." {
+					contract.Bases[len(contract.Bases)-1] += "." + t.Text
+				} else {
+					contract.Bases = append(contract.Bases, t.Text)
+				}
+			}
+		}
+	}
+
+	if p.eof() {
+		p.unexpectedEOF()
+	}
+	if p.accept("{") {
+		for !p.eof() && p.peek().Text != "}" {
+			p.parseMember(contract)
+		}
+		if !p.accept("}") {
+			p.unexpectedEOF()
+		}
+	}
+
+	contract.Range = p.rangeFrom(start)
+	return contract
+}
+
+func (p *parser) parseMember(contract *Contract) {
+	switch p.peek().Text {
+	case "function":
+		contract.Functions = append(contract.Functions, p.parseFunction(FUNCTION))
+	case "constructor":
+		contract.Functions = append(contract.Functions, p.parseFunction(CONSTRUCTOR))
+	case "fallback":
+		contract.Functions = append(contract.Functions, p.parseFunction(FALLBACK))
+	case "receive":
+		contract.Functions = append(contract.Functions, p.parseFunction(RECEIVE))
+	case "modifier":
+		contract.Functions = append(contract.Functions, p.parseFunction(MODIFIER))
+	case "struct", "enum", "event", "error", "using", "type":
+		p.skipDefinition()
+	default:
+		if v := p.parseStateVariable(); v != nil {
+			contract.StateVariables = append(contract.StateVariables, v)
+		}
+	}
+}
+
+func (p *parser) parseFunction(kind FunctionKind) *Function {
+	start := p.pos
+	p.next()
+
+	function := &Function{
+		Kind:       kind,
+		Modifiers:  []string{},
+		Parameters: []*Parameter{},
+		Returns:    []*Parameter{},
+		Statements: []*Statement{},
+	}
+	if (kind == FUNCTION || kind == MODIFIER) && !p.eof() && p.peek().Kind == IDENTIFIER {
+		function.Name = p.next().Text
+	}
+	if !p.eof() && p.peek().Text == "(" {
+		function.Parameters = p.parseParameters()
+	}
+
+	for !p.eof() {
+		t := p.peek()
+		switch t.Text {
+		case "external", "public", "internal", "private":
+			function.Visibility = t.Text
+		case "pure", "view", "payable", "constant":
+			function.StateMutability = t.Text
+		case "virtual":
+			function.Virtual = true
+		case "override":
+			function.Override = true
+			p.next()
+			if !p.eof() && p.peek().Text == "(" {
+				p.pos = p.skipBalanced(p.pos) + 1
+			}
+			continue
+		case "returns":
+			p.next()
+			if !p.eof() && p.peek().Text == "(" {
+				function.Returns = p.parseParameters()
+			}
+			continue
+		case ";":
+			p.next()
+			function.Range = p.rangeFrom(start)
+			return function
+		case "{":
+			end := p.skipBalanced(p.pos)
+			body := p.rangeOf(p.pos, end)
+			function.Body = &body
+			function.Statements = p.parseStatements(p.pos+1, end)
+			p.pos = end + 1
+			function.Range = p.rangeFrom(start)
+			return function
+		default:
+			if t.Kind != IDENTIFIER {
+				// Not a function header, give up on this function.
+				function.Range = p.rangeFrom(start)
+				return function
+			}
+			// Modifier invocation, possibly with arguments.
+			function.Modifiers = append(function.Modifiers, t.Text)
+			p.next()
+			if !p.eof() && p.peek().Text == "(" {
+				p.pos = p.skipBalanced(p.pos) + 1
+			}
+			continue
+		}
+		p.next()
+	}
+
+	p.unexpectedEOF()
+	function.Range = p.rangeFrom(start)
+	return function
+}
+
+// parseParameters parses a parenthesized parameter list at the current
+// position.
+func (p *parser) parseParameters() []*Parameter {
+	open := p.pos
+	end := p.skipBalanced(open)
+	p.pos = end + 1
+
+	parameters := []*Parameter{}
+	for _, group := range p.splitList(open+1, end) {
+		tokens := p.tokens[group[0]:group[1]]
+		if len(tokens) == 0 {
+			continue
+		}
+
+		parameter := &Parameter{
+			Range: p.rangeOf(group[0], group[1]-1),
+		}
+
+		typeEnd := len(tokens)
+		last := tokens[len(tokens)-1]
+		if len(tokens) > 1 && last.Kind == IDENTIFIER && !isDataLocation(last.Text) &&
+			!(last.Text == "payable" && tokens[len(tokens)-2].Text == "address") {
+			parameter.Name = last.Text
+			typeEnd--
+		}
+		if typeEnd > 1 && isDataLocation(tokens[typeEnd-1].Text) {
+			parameter.DataLocation = tokens[typeEnd-1].Text
+			typeEnd--
+		}
+		parameter.Type = p.text(tokens[0], tokens[typeEnd-1])
+
+		parameters = append(parameters, parameter)
+	}
+
+	return parameters
+}
+
+// parseStateVariable parses a state variable declaration. It returns nil if
+// the tokens up to the next `;` are not a declaration.
+func (p *parser) parseStateVariable() *StateVariable {
+	start := p.pos
+
+	// Find the end of the declaration and its initializer, if any.
+	declarationEnd := -1
+	depth := 0
+	for !p.eof() {
+		t := p.next()
+		switch t.Text {
+		case "(", "[":
+			depth++
+		case ")", "]":
+			depth--
+		case "=":
+			if depth == 0 && declarationEnd == -1 {
+				declarationEnd = p.pos - 1
+			}
+		case "{":
+			if depth == 0 {
+				// Not a declaration.
+				p.pos = p.skipBalanced(p.pos-1) + 1
+				return nil
+			}
+		case "}":
+			if depth == 0 {
+				// End of the contract, leave it to the caller.
+				p.pos--
+				return nil
+			}
+		}
+		if t.Text == ";" && depth == 0 {
+			break
+		}
+	}
+	end := p.pos - 1
+	if declarationEnd == -1 {
+		declarationEnd = end
+	}
+
+	tokens := p.tokens[start:declarationEnd]
+	if len(tokens) < 2 || tokens[len(tokens)-1].Kind != IDENTIFIER {
+		return nil
+	}
+
+	variable := &StateVariable{
+		Name:  tokens[len(tokens)-1].Text,
+		Range: p.rangeOf(start, end),
+	}
+
+	typeEnd := -1
+	for i, t := range tokens[:len(tokens)-1] {
+		switch t.Text {
+		case "public", "private", "internal", "external":
+			variable.Visibility = t.Text
+		case "constant", "immutable":
+			variable.Mutability = t.Text
+		case "override", "transient":
+		default:
+			continue
+		}
+		if typeEnd == -1 {
+			typeEnd = i
+		}
+	}
+	if typeEnd == -1 {
+		typeEnd = len(tokens) - 1
+	}
+	if typeEnd == 0 {
+		return nil
+	}
+	variable.Type = p.text(tokens[0], tokens[typeEnd-1])
+
+	return variable
+}
+
+// parseStatements splits the tokens [from, to) of a function body into
+// statements.
+func (p *parser) parseStatements(from, to int) []*Statement {
+	statements := []*Statement{}
+	emit := func(start, end, depth int) {
+		statements = append(statements, &Statement{
+			Depth: depth,
+			Range: p.rangeOf(start, end),
+		})
+	}
+
+	depth := 0
+	parens := 0
+	start := -1
+	for i := from; i < to; i++ {
+		t := p.tokens[i]
+		if start == -1 {
+			switch t.Text {
+			case "}":
+				depth--
+				continue
+			case "{":
+				depth++
+				continue
+			}
+			start = i
+		}
+
+		switch t.Text {
+		case "(", "[":
+			parens++
+		case ")", "]":
+			parens--
+		case ";":
+			if parens <= 0 {
+				emit(start, i, depth)
+				start = -1
+			}
+		case "{":
+			if parens <= 0 {
+				emit(start, i, depth)
+				depth++
+				start = -1
+			}
+		case "}":
+			if parens <= 0 {
+				// Statements without a terminating `;`, as in assembly.
+				emit(start, i-1, depth)
+				depth--
+				start = -1
+			}
+		}
+	}
+	if start != -1 {
+		emit(start, to-1, depth)
+	}
+
+	return statements
+}
+
+// skipDefinition skips to the end of the definition at the current position,
+// which is either a `;` or a block.
+func (p *parser) skipDefinition() {
+	start := p.pos
+	depth := 0
+	for !p.eof() {
+		t := p.next()
+		switch t.Text {
+		case "(", "[":
+			depth++
+		case ")", "]":
+			depth--
+		case ";":
+			if depth <= 0 {
+				return
+			}
+		case "{":
+			if depth <= 0 {
+				p.pos = p.skipBalanced(p.pos-1) + 1
+				return
+			}
+		case "}":
+			if depth <= 0 {
+				// Closing brace of the enclosing block, let the caller
+				// handle it unless it is a stray one.
+				if p.pos-1 > start {
+					p.pos--
+				}
+				return
+			}
+		}
+	}
+}
+
+// skipBalanced returns the index of the token closing the bracket at token
+// index `open`, or the last token index if it is not closed. Unclosed
+// brackets are recorded as unexpected end of file.
+func (p *parser) skipBalanced(open int) int {
+	opening := p.tokens[open].Text
+	closing := map[string]string{"(": ")", "[": "]", "{": "}"}[opening]
+
+	depth := 0
+	for i := open; i < len(p.tokens); i++ {
+		switch p.tokens[i].Text {
+		case opening:
+			depth++
+		case closing:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	p.unexpectedEOF()
+	return len(p.tokens) - 1
+}
+
+// splitList splits the tokens [from, to) at top-level commas and returns
+// the token index ranges of the elements.
+func (p *parser) splitList(from, to int) [][2]int {
+	elements := [][2]int{}
+	depth := 0
+	start := from
+	for i := from; i < to; i++ {
+		switch p.tokens[i].Text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+		case ",":
+			if depth == 0 {
+				elements = append(elements, [2]int{start, i})
+				start = i + 1
+			}
+		}
+	}
+	if start < to {
+		elements = append(elements, [2]int{start, to})
+	}
+	return elements
+}
+
+// unexpectedEOF records that the source ends inside a definition, unless an
+// error was recorded before.
+func (p *parser) unexpectedEOF() {
+	if p.err == nil {
+		p.err = fmt.Errorf("line %d: unexpected end of file", p.line(len(p.src)))
+	}
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() Token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() Token {
+	t := p.tokens[p.pos]
+	p.pos++
+	return t
+}
+
+// accept consumes the next token if its text is `text`.
+func (p *parser) accept(text string) bool {
+	if !p.eof() && p.peek().Text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// text returns the source from token `from` to token `to` with whitespace
+// and comments collapsed to single spaces.
+func (p *parser) text(from, to Token) string {
+	return strings.Join(strings.Fields(p.src[from.Start:to.End]), " ")
+}
+
+// rangeFrom returns the range from token index `start` up to the last
+// consumed token.
+func (p *parser) rangeFrom(start int) Range {
+	end := p.pos - 1
+	if end < start {
+		end = start
+	}
+	return p.rangeOf(start, end)
+}
+
+// rangeOf returns the range from token index `start` to token index `end`,
+// both inclusive.
+func (p *parser) rangeOf(start, end int) Range {
+	if start >= len(p.tokens) {
+		return Range{len(p.src), len(p.src), p.line(len(p.src)), p.line(len(p.src))}
+	}
+	if end >= len(p.tokens) {
+		end = len(p.tokens) - 1
+	}
+
+	r := Range{
+		Start: p.tokens[start].Start,
+		End:   p.tokens[end].End,
+	}
+	r.StartLine = p.line(r.Start)
+	r.EndLine = p.line(r.End - 1)
+	return r
+}
+
+// line returns the 1-based line number of byte `offset`.
+func (p *parser) line(offset int) int {
+	return sort.Search(len(p.lines), func(i int) bool { return p.lines[i] > offset })
+}
+
+func isDataLocation(s string) bool {
+	return s == "memory" || s == "storage" || s == "calldata"
+}
@@ -0,0 +1,170 @@
+package solidity
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const source = `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+import "./IERC20.sol";
+import {Ownable} from "@openzeppelin/contracts/access/Ownable.sol";
+
+interface IVault {
+    function deposit(uint256 amount) external;
+}
+
+abstract contract Vault is IVault, Ownable(msg.sender) {
+    struct Position { uint256 amount; }
+
+    uint256 public constant FEE = 10; // { not a block
+    mapping(address => Position) internal positions;
+    address payable immutable treasury;
+
+    event Deposited(address indexed user, uint256 amount);
+
+    modifier nonZero(uint256 amount) {
+        require(amount != 0, "zero; amount");
+        _;
+    }
+
+    function deposit(
+        uint256 amount
+    ) external override nonZero(amount) onlyOwner {
+        positions[msg.sender].amount += amount;
+        if (amount > FEE) {
+            emit Deposited(msg.sender, amount);
+        }
+    }
+
+    function batch(address[] memory users, bytes calldata) public view virtual returns (uint256[] memory amounts);
+
+    receive() external payable {}
+}
+`
+
+func TestParse(t *testing.T) {
+	unit, err := Parse(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(unit.Pragmas) != 1 || unit.Pragmas[0].Name != "solidity" || unit.Pragmas[0].Value != "^0.8.0" {
+		t.Errorf("unexpected pragmas: %+v", unit.Pragmas)
+	}
+
+	imports := []string{}
+	for _, imp := range unit.Imports {
+		imports = append(imports, imp.Path)
+	}
+	if !reflect.DeepEqual(imports, []string{"./IERC20.sol", "@openzeppelin/contracts/access/Ownable.sol"}) {
+		t.Errorf("unexpected imports: %v", imports)
+	}
+
+	if len(unit.Contracts) != 2 {
+		t.Fatalf("got %d contracts, want 2", len(unit.Contracts))
+	}
+	if c := unit.Contracts[0]; c.Kind != INTERFACE || c.Name != "IVault" || len(c.Functions) != 1 || c.Functions[0].Body != nil {
+		t.Errorf("unexpected interface: %+v", c)
+	}
+
+	vault := unit.Contracts[1]
+	if !vault.Abstract || vault.Name != "Vault" || !reflect.DeepEqual(vault.Bases, []string{"IVault", "Ownable"}) {
+		t.Errorf("unexpected contract: %+v", vault)
+	}
+	if vault.Range.StartLine != 11 || vault.Range.EndLine != 37 {
+		t.Errorf("contract spans lines %d-%d, want 11-37", vault.Range.StartLine, vault.Range.EndLine)
+	}
+
+	variables := []StateVariable{}
+	for _, v := range vault.StateVariables {
+		variables = append(variables, StateVariable{Type: v.Type, Name: v.Name, Visibility: v.Visibility, Mutability: v.Mutability})
+	}
+	wantVariables := []StateVariable{
+		{Type: "uint256", Name: "FEE", Visibility: "public", Mutability: "constant"},
+		{Type: "mapping(address => Position)", Name: "positions", Visibility: "internal"},
+		{Type: "address payable", Name: "treasury", Mutability: "immutable"},
+	}
+	if !reflect.DeepEqual(variables, wantVariables) {
+		t.Errorf("got state variables %+v, want %+v", variables, wantVariables)
+	}
+
+	if len(vault.Functions) != 4 {
+		t.Fatalf("got %d functions, want 4", len(vault.Functions))
+	}
+	modifier, deposit, batch, receive := vault.Functions[0], vault.Functions[1], vault.Functions[2], vault.Functions[3]
+
+	if modifier.Kind != MODIFIER || modifier.Name != "nonZero" || len(modifier.Statements) != 2 {
+		t.Errorf("unexpected modifier: %+v", modifier)
+	}
+
+	if deposit.Visibility != "external" || !deposit.Override || !reflect.DeepEqual(deposit.Modifiers, []string{"nonZero", "onlyOwner"}) {
+		t.Errorf("unexpected function: %+v", deposit)
+	}
+	if deposit.Range.StartLine != 25 || deposit.Body.StartLine != 27 || deposit.Body.EndLine != 32 {
+		t.Errorf("unexpected function ranges: %+v, body %+v", deposit.Range, deposit.Body)
+	}
+	statements := [][3]int{}
+	for _, s := range deposit.Statements {
+		statements = append(statements, [3]int{s.Depth, s.Range.StartLine, s.Range.EndLine})
+	}
+	if !reflect.DeepEqual(statements, [][3]int{{0, 28, 28}, {0, 29, 29}, {1, 30, 30}}) {
+		t.Errorf("unexpected statements: %v", statements)
+	}
+
+	parameters := []Parameter{}
+	for _, p := range append(batch.Parameters, batch.Returns...) {
+		parameters = append(parameters, Parameter{Type: p.Type, DataLocation: p.DataLocation, Name: p.Name})
+	}
+	wantParameters := []Parameter{
+		{Type: "address[]", DataLocation: "memory", Name: "users"},
+		{Type: "bytes", DataLocation: "calldata"},
+		{Type: "uint256[]", DataLocation: "memory", Name: "amounts"},
+	}
+	if !reflect.DeepEqual(parameters, wantParameters) {
+		t.Errorf("got parameters %+v, want %+v", parameters, wantParameters)
+	}
+	if batch.Visibility != "public" || batch.StateMutability != "view" || !batch.Virtual || batch.Body != nil {
+		t.Errorf("unexpected function: %+v", batch)
+	}
+
+	if receive.Kind != RECEIVE || receive.StateMutability != "payable" || receive.Body == nil {
+		t.Errorf("unexpected receive function: %+v", receive)
+	}
+}
+
+func TestParseTruncated(t *testing.T) {
+	for _, src := range []string{
+		"abstract",
+		"contract",
+		"contract C is",
+		"contract C is A(",
+		"contract C {",
+		"contract C { function f() external {",
+		"function f(",
+		"function f() external",
+	} {
+		unit, err := Parse(src)
+		if err == nil || !strings.Contains(err.Error(), "unexpected end of file") {
+			t.Errorf("%q: got error %v, want unexpected end of file", src, err)
+		}
+		if unit == nil {
+			t.Errorf("%q: got no partially parsed unit", src)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	src := `a = "b\" // c"; // d "e
+/* f */ x = 'g`
+	texts := []string{}
+	for _, token := range Tokenize(src) {
+		texts = append(texts, token.Text)
+	}
+	want := []string{"a", "=", `"b\" // c"`, ";", "x", "=", "'g"}
+	if !reflect.DeepEqual(texts, want) {
+		t.Errorf("got tokens %q, want %q", texts, want)
+	}
+}
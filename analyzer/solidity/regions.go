@@ -0,0 +1,62 @@
+package solidity
+
+import "strings"
+
+// Region classifies a byte of Solidity source.
+type Region byte
+
+// The Region Enum.
+const (
+	CODE Region = iota
+	COMMENT
+	LITERAL
+)
+
+// Regions tags every byte of the Solidity source `src` as code, comment or
+// string literal content. Quotes and the `hex`/`unicode` prefixes of
+// literals count as code, comment markers count as comment. Block comments
+// may span lines, string literals may not.
+func Regions(src string) []Region {
+	regions := make([]Region, len(src))
+
+	for i := 0; i < len(src); {
+		switch {
+		case strings.HasPrefix(src[i:], "//"):
+			// Line comment, up to but excluding the newline.
+			for i < len(src) && src[i] != '\n' {
+				regions[i] = COMMENT
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			// Block comment, possibly unterminated.
+			end := strings.Index(src[i+2:], "*/")
+			if end == -1 {
+				end = len(src)
+			} else {
+				end += i + 4
+			}
+			for ; i < end; i++ {
+				regions[i] = COMMENT
+			}
+		case src[i] == '"' || src[i] == '\'':
+			// String literal. The quotes stay code, the content is literal.
+			quote := src[i]
+			i++
+			for i < len(src) && src[i] != quote && src[i] != '\n' {
+				if src[i] == '\\' && i+1 < len(src) && src[i+1] != '\n' {
+					regions[i] = LITERAL
+					i++
+				}
+				regions[i] = LITERAL
+				i++
+			}
+			if i < len(src) && src[i] == quote {
+				i++
+			}
+		default:
+			i++
+		}
+	}
+
+	return regions
+}
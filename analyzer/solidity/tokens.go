@@ -0,0 +1,98 @@
+package solidity
+
+import (
+	"strings"
+)
+
+// TokenKind type defining the kind of a Token.
+type TokenKind int
+
+// The TokenKind Enum.
+const (
+	IDENTIFIER TokenKind = iota
+	NUMBER
+	STRING
+	PUNCTUATION
+)
+
+// Token is a lexical token of Solidity source. Comments and whitespace are
+// not tokens.
+type Token struct {
+	Kind  TokenKind
+	Text  string
+	Start int
+	End   int
+}
+
+// punctuation lists multi-character operators, longest first.
+var punctuation = []string{
+	">>>=", "<<=", ">>=", ">>>",
+	"=>", "==", "!=", "<=", ">=", "&&", "||", "++", "--", "+=", "-=", "*=",
+	"/=", "%=", "|=", "&=", "^=", "**", "<<", ">>", "->", ":=",
+}
+
+// Tokenize splits `src` into tokens, telling comments and strings apart
+// with Regions. It never fails, unknown characters become single character
+// punctuation tokens.
+func Tokenize(src string) []Token {
+	tokens := []Token{}
+	regions := Regions(src)
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case regions[i] == COMMENT || c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			start := i
+			i++
+			for i < len(src) && regions[i] == LITERAL {
+				i++
+			}
+			if i < len(src) && src[i] == c {
+				i++
+			}
+			tokens = append(tokens, Token{STRING, src[start:i], start, i})
+		case isIdentifierStart(c):
+			start := i
+			for i < len(src) && isIdentifierPart(src[i]) {
+				i++
+			}
+			tokens = append(tokens, Token{IDENTIFIER, src[start:i], start, i})
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (isIdentifierPart(src[i]) || src[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, Token{NUMBER, src[start:i], start, i})
+		default:
+			start := i
+			i++
+			for _, p := range punctuation {
+				if strings.HasPrefix(src[start:], p) {
+					i = start + len(p)
+					break
+				}
+			}
+			tokens = append(tokens, Token{PUNCTUATION, src[start:i], start, i})
+		}
+	}
+
+	return tokens
+}
+
+// Value returns the content of a STRING token without its quotes.
+func (t Token) Value() string {
+	if t.Kind != STRING || len(t.Text) < 2 {
+		return t.Text
+	}
+	return t.Text[1 : len(t.Text)-1]
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentifierPart(c byte) bool {
+	return isIdentifierStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,143 @@
+// Package solidity parses Solidity source files into a structural model of
+// their pragmas, imports, contracts, state variables and functions.
+//
+// The parser is not a compiler front end. It does not parse expressions and
+// tolerates invalid code, returning as much of the model as it recognizes.
+package solidity
+
+// Range is a region of a source file. Start and End are byte offsets of
+// the half-open range [Start, End), lines are 1-based and inclusive.
+type Range struct {
+	Start     int
+	End       int
+	StartLine int
+	EndLine   int
+}
+
+// SourceUnit is a parsed Solidity source file.
+type SourceUnit struct {
+	Pragmas   []Pragma
+	Imports   []Import
+	Contracts []*Contract
+	// Free functions declared outside of any contract.
+	Functions []*Function
+}
+
+// Pragma is a pragma directive such as `pragma solidity ^0.8.0;`.
+type Pragma struct {
+	Name  string
+	Value string
+	Range Range
+}
+
+// Import is an import directive.
+type Import struct {
+	Path  string
+	Range Range
+}
+
+// ContractKind type defining the kind of a Contract.
+type ContractKind int
+
+// The ContractKind Enum.
+const (
+	CONTRACT ContractKind = iota
+	INTERFACE
+	LIBRARY
+)
+
+// Contract is a contract, interface or library definition.
+type Contract struct {
+	Kind     ContractKind
+	Abstract bool
+	Name     string
+	// Names of the inherited contracts, in declaration order.
+	Bases          []string
+	StateVariables []*StateVariable
+	// Functions, including constructors, fallback and receive functions and
+	// modifier definitions.
+	Functions []*Function
+	Range     Range
+}
+
+// StateVariable is a state variable declaration.
+type StateVariable struct {
+	Type       string
+	Name       string
+	Visibility string
+	// Mutability is "constant", "immutable" or empty.
+	Mutability string
+	Range      Range
+}
+
+// FunctionKind type defining the kind of a Function.
+type FunctionKind int
+
+// The FunctionKind Enum.
+const (
+	FUNCTION FunctionKind = iota
+	CONSTRUCTOR
+	FALLBACK
+	RECEIVE
+	MODIFIER
+)
+
+// Function is a function, constructor, fallback or receive function, or a
+// modifier definition.
+type Function struct {
+	Kind FunctionKind
+	// Name is empty for constructors, fallback and receive functions.
+	Name       string
+	Visibility string
+	// StateMutability is "pure", "view", "payable" or empty.
+	StateMutability string
+	Virtual         bool
+	Override        bool
+	// Names of the modifiers invoked by the function.
+	Modifiers  []string
+	Parameters []*Parameter
+	Returns    []*Parameter
+	// Body is nil if the function is not implemented.
+	Body *Range
+	// Statements of the body, including those of nested blocks, in source
+	// order.
+	Statements []*Statement
+	Range      Range
+}
+
+// Parameter is a function parameter or return variable.
+type Parameter struct {
+	Type string
+	// DataLocation is "memory", "storage", "calldata" or empty.
+	DataLocation string
+	// Name is empty for unnamed parameters.
+	Name  string
+	Range Range
+}
+
+// Statement is a statement within a function body. Statements opening a
+// block, such as `if (x) {`, end with the opening brace.
+type Statement struct {
+	// Depth is the block nesting depth, 0 for statements directly in the
+	// function body.
+	Depth int
+	Range Range
+}
+
+func (k ContractKind) String() string {
+	return []string{
+		"contract",
+		"interface",
+		"library",
+	}[k]
+}
+
+func (k FunctionKind) String() string {
+	return []string{
+		"function",
+		"constructor",
+		"fallback",
+		"receive",
+		"modifier",
+	}[k]
+}
@@ -0,0 +1,121 @@
+package analyzer
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/byterocket/c4udit/analyzer/solidity"
+)
+
+// SourceFile is a Solidity source file prepared for analysis. It is handed
+// to every Detector.
+type SourceFile struct {
+	// Path of the file relative to the project root.
+	Path string
+	// Content is the raw file content.
+	Content string
+	// Unit is the parsed structure of Content.
+	Unit *solidity.SourceUnit
+
+	lexed      *lexedSource
+	lines      []string
+	index      lineIndex
+	statements []statement
+}
+
+// NewSourceFile prepares `content` of the file at `path` for analysis. It
+// fails if the content can not be parsed, e.g. because it is truncated.
+func NewSourceFile(path string, content string) (*SourceFile, error) {
+	lexed := lex(content)
+	unit, err := solidity.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SourceFile{
+		Path:       path,
+		Content:    content,
+		Unit:       unit,
+		lexed:      lexed,
+		lines:      strings.Split(content, "\n"),
+		index:      newLineIndex(content),
+		statements: splitStatements(lexed),
+	}, nil
+}
+
+// Code returns the content with comments and string literal contents
+// replaced by spaces. Offsets into it are offsets into Content.
+func (f *SourceFile) Code() string {
+	return f.lexed.view(solidity.CODE)
+}
+
+// Comments returns the content with everything but comments replaced by
+// spaces. Offsets into it are offsets into Content.
+func (f *SourceFile) Comments() string {
+	return f.lexed.view(solidity.COMMENT)
+}
+
+// Finding returns a Finding for the bytes [start, end) of Content.
+// The IssueIdentifier is filled in by the analyzer.
+func (f *SourceFile) Finding(start, end int) Finding {
+	startLine := f.index.line(start)
+	endLine := startLine
+	if end > start {
+		endLine = f.index.line(end - 1)
+	}
+
+	content := []string{}
+	for _, line := range f.lines[startLine-1 : endLine] {
+		content = append(content, strings.TrimSpace(line))
+	}
+
+	return Finding{
+		File:          f.Path,
+		LineNumber:    startLine,
+		EndLineNumber: endLine,
+		Column:        f.column(start),
+		EndColumn:     utf8.RuneCountInString(f.Content[f.index[endLine-1]:end]) + 1,
+		Match:         f.Content[start:end],
+		LineContent:   strings.Join(content, " "),
+		Function:      f.enclosingFunction(start),
+	}
+}
+
+// enclosingFunction returns the name of the function containing byte
+// `offset` as `Contract.function`, or an empty string.
+func (f *SourceFile) enclosingFunction(offset int) string {
+	contains := func(r solidity.Range) bool {
+		return r.Start <= offset && offset < r.End
+	}
+
+	for _, c := range f.Unit.Contracts {
+		if !contains(c.Range) {
+			continue
+		}
+		for _, fn := range c.Functions {
+			if contains(fn.Range) {
+				return c.Name + "." + functionName(fn)
+			}
+		}
+	}
+	for _, fn := range f.Unit.Functions {
+		if contains(fn.Range) {
+			return functionName(fn)
+		}
+	}
+	return ""
+}
+
+// functionName returns the name of `fn`, or its kind for unnamed functions.
+func functionName(fn *solidity.Function) string {
+	if fn.Name == "" {
+		return fn.Kind.String()
+	}
+	return fn.Name
+}
+
+// column returns the 1-based character column of byte `offset`.
+func (f *SourceFile) column(offset int) int {
+	line := f.index.line(offset)
+	return utf8.RuneCountInString(f.Content[f.index[line-1]:offset]) + 1
+}
@@ -0,0 +1,108 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/byterocket/c4udit/analyzer/solidity"
+)
+
+// statement is a logical Solidity statement spanning the byte range
+// [start, end) of its source. It runs up to and including the next `;`, `{`
+// or `}` in code that is not nested in parentheses. An empty block directly
+// following a `{` is kept in the same statement, so `function f() {}` is one
+// statement.
+type statement struct {
+	start int
+	end   int
+}
+
+// splitStatements splits a lexed source into logical statements. Leading
+// whitespace and comments are not part of a statement, statements without
+// any code are dropped.
+func splitStatements(source *lexedSource) []statement {
+	code := source.view(solidity.CODE)
+
+	statements := []statement{}
+	start := -1
+	depth := 0
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		if start == -1 {
+			if isSpace(c) {
+				continue
+			}
+			start = i
+		}
+
+		switch c {
+		case '(':
+			depth++
+			continue
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+			continue
+		case ';', '{', '}':
+			if depth > 0 {
+				continue
+			}
+		default:
+			continue
+		}
+
+		// Keep empty blocks attached to their header.
+		if c == '{' {
+			j := i + 1
+			for j < len(code) && isSpace(code[j]) {
+				j++
+			}
+			if j < len(code) && code[j] == '}' {
+				i = j
+			}
+		}
+
+		statements = append(statements, statement{start, i + 1})
+		start = -1
+	}
+
+	if start != -1 {
+		statements = append(statements, statement{start, len(strings.TrimRightFunc(code, isSpaceRune))})
+	}
+
+	return statements
+}
+
+// lineIndex maps byte offsets of a source to line numbers.
+type lineIndex []int
+
+// newLineIndex returns the line index of `src`.
+func newLineIndex(src string) lineIndex {
+	starts := lineIndex{0}
+	for i := 0; i < len(src); i++ {
+		if src[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// line returns the 1-based line number of byte `offset`.
+func (l lineIndex) line(offset int) int {
+	return sort.Search(len(l), func(i int) bool { return l[i] > offset })
+}
+
+// flatten returns `s` with line breaks replaced by spaces, so that patterns
+// can match across lines without changing any offsets.
+func flatten(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isSpaceRune(r rune) bool {
+	return r < 0x80 && isSpace(byte(r))
+}
@@ -0,0 +1,38 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	src := strings.Join([]string{
+		`function f(`,
+		`    uint a // comment; {`,
+		`) external {}`,
+		`for (uint i; i < n; ++i) {`,
+		`    require(`,
+		`        x > 0,`,
+		`        "a; b"`,
+		`    );`,
+		`}`,
+	}, "\n")
+
+	source := lex(src)
+	index := newLineIndex(src)
+
+	want := [][2]int{{1, 3}, {4, 4}, {5, 8}, {9, 9}}
+	got := [][2]int{}
+	for _, s := range splitStatements(source) {
+		got = append(got, [2]int{index.line(s.start), index.line(s.end - 1)})
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got statements %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("statement %d spans lines %v, want %v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Suppression is a comment that suppresses findings:
+//
+//	// c4udit-disable-next-line G-06,N-02
+//	// c4udit-disable-line
+//	// c4udit-disable G-06
+//	// c4udit-enable G-06
+//
+// Without identifiers all issues are suppressed. `c4udit-disable` suppresses
+// up to the matching `c4udit-enable` or the end of the file. A bare
+// `c4udit-enable` ends all open regions, `c4udit-enable G-06` only ends the
+// suppression of G-06 in regions listing it.
+type Suppression struct {
+	File      string
+	Line      int
+	Directive string
+	// Count is the number of findings suppressed.
+	Count int
+}
+
+// suppressedRegion is a range of lines, inclusive, in which a Suppression
+// applies to `issues`, or to all issues if empty.
+type suppressedRegion struct {
+	suppression *Suppression
+	issues      []string
+	start       int
+	end         int
+}
+
+var suppressionPattern = regexp.MustCompile(`c4udit-(disable-next-line|disable-line|disable|enable)\b((?:[ \t]*,?[ \t]*[A-Za-z]+-\w+)*)`)
+
+// parseSuppressions returns the suppressions in the comments of `file` and
+// the regions they apply to.
+func parseSuppressions(file *SourceFile) ([]*Suppression, []*suppressedRegion) {
+	suppressions := []*Suppression{}
+	regions := []*suppressedRegion{}
+	open := []*suppressedRegion{}
+
+	for i, line := range strings.Split(file.Comments(), "\n") {
+		lineNumber := i + 1
+
+		m := suppressionPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		issues := strings.FieldsFunc(m[2], func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+
+		if m[1] == "enable" {
+			stillOpen := []*suppressedRegion{}
+			for _, r := range open {
+				closed, remaining := r.close(issues, lineNumber)
+				if closed != nil {
+					regions = append(regions, closed)
+				}
+				if remaining != nil {
+					stillOpen = append(stillOpen, remaining)
+				}
+			}
+			open = stillOpen
+			continue
+		}
+
+		s := &Suppression{
+			File:      file.Path,
+			Line:      lineNumber,
+			Directive: strings.TrimSpace(m[0]),
+		}
+		suppressions = append(suppressions, s)
+
+		r := &suppressedRegion{
+			suppression: s,
+			issues:      issues,
+		}
+		switch m[1] {
+		case "disable-next-line":
+			r.start, r.end = lineNumber+1, lineNumber+1
+			regions = append(regions, r)
+		case "disable-line":
+			r.start, r.end = lineNumber, lineNumber
+			regions = append(regions, r)
+		case "disable":
+			r.start = lineNumber
+			open = append(open, r)
+		}
+	}
+
+	// Regions not enabled again last until the end of the file.
+	for _, r := range open {
+		r.end = len(file.lines)
+		regions = append(regions, r)
+	}
+
+	return suppressions, regions
+}
+
+// close ends open region `r` at line `end` by enabling `issues`, or all
+// issues if empty. It returns the ended region, if any, and the region that
+// stays open, if any.
+func (r *suppressedRegion) close(issues []string, end int) (*suppressedRegion, *suppressedRegion) {
+	if len(issues) == 0 {
+		closed := *r
+		closed.end = end
+		return &closed, nil
+	}
+	if len(r.issues) == 0 {
+		// Regions suppressing all issues are only ended by a bare
+		// `c4udit-enable`.
+		return nil, r
+	}
+
+	remaining := []string{}
+	for _, id := range r.issues {
+		if !contains(issues, id) {
+			remaining = append(remaining, id)
+		}
+	}
+	if len(remaining) == len(r.issues) {
+		return nil, r
+	}
+
+	closed := *r
+	closed.end = end
+	if len(remaining) == 0 {
+		return &closed, nil
+	}
+
+	// The remaining issues stay suppressed.
+	rest := *r
+	rest.issues = remaining
+	rest.start = end
+	return &closed, &rest
+}
+
+// suppresses reports whether region `r` covers finding `f`.
+func (r *suppressedRegion) suppresses(f Finding) bool {
+	if len(r.issues) > 0 && !contains(r.issues, f.IssueIdentifier) {
+		return false
+	}
+	end := f.EndLineNumber
+	if end < f.LineNumber {
+		end = f.LineNumber
+	}
+	return f.LineNumber <= r.end && end >= r.start
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
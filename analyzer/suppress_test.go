@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const suppressedSource = `contract S {
+    uint a; // c4udit-disable-line N-02
+    // c4udit-disable-next-line
+    uint b;
+    // c4udit-disable N-02, G-05
+    uint c = 0;
+    // c4udit-enable N-02
+    uint d = 0;
+    // c4udit-enable
+    uint e = 0; // c4udit-disable-line G-06
+}
+`
+
+func TestSuppressions(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "S.sol"), []byte(suppressedSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+	issues, err := SelectIssues(AllIssues(), Selection{Only: []string{"N-02", "G-05"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := Run(issues, []string{dir}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := map[string][]int{}
+	for id, findings := range report.FindingsPerIssue {
+		for _, f := range findings {
+			lines[id] = append(lines[id], f.LineNumber)
+		}
+	}
+	want := map[string][]int{
+		"N-02": {8, 10},
+		"G-05": {10},
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("got findings on lines %v, want %v", lines, want)
+	}
+	if want := map[string]int{"N-02": 3, "G-05": 2}; !reflect.DeepEqual(report.SuppressedPerIssue, want) {
+		t.Errorf("got suppressed findings %v, want %v", report.SuppressedPerIssue, want)
+	}
+
+	counts := map[int]int{}
+	for _, s := range report.Suppressions {
+		counts[s.Line] = s.Count
+	}
+	if want := map[int]int{2: 1, 3: 1, 5: 3, 10: 0}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("got suppression counts per line %v, want %v", counts, want)
+	}
+
+	unused := []int{}
+	for _, s := range report.UnusedSuppressions() {
+		unused = append(unused, s.Line)
+	}
+	if !reflect.DeepEqual(unused, []int{10}) {
+		t.Errorf("got unused suppressions on lines %v, want [10]", unused)
+	}
+}
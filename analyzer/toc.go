@@ -0,0 +1,172 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// The table of contents is enclosed in markers so that it can be found and
+// refreshed later on.
+const (
+	tocStart   = "<!-- toc -->"
+	tocStop    = "<!-- tocstop -->"
+	tocHeading = "Table of Contents"
+)
+
+var (
+	atxHeadingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	fencePattern      = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+)
+
+// markdownHeading is a heading of a Markdown document.
+type markdownHeading struct {
+	level  int
+	text   string
+	anchor string
+}
+
+// markdownWriter builds a Markdown document and keeps track of its
+// headings and their anchors.
+type markdownWriter struct {
+	strings.Builder
+	anchors  anchors
+	headings []markdownHeading
+}
+
+func newMarkdownWriter() *markdownWriter {
+	return &markdownWriter{anchors: newAnchors()}
+}
+
+// heading writes a heading of level `level`.
+func (w *markdownWriter) heading(level int, text string) {
+	w.headings = append(w.headings, markdownHeading{level, text, w.anchors.anchor(text)})
+	w.WriteString(strings.Repeat("#", level) + " " + text + "\n")
+}
+
+// reserve claims the anchor of a heading that is inserted later on at the
+// current position.
+func (w *markdownWriter) reserve(text string) {
+	w.anchors.anchor(text)
+}
+
+// tableOfContents returns the table of contents linking the level 2 and 3
+// `headings`, with lines ending in `newline`.
+func tableOfContents(headings []markdownHeading, newline string) string {
+	buf := strings.Builder{}
+	buf.WriteString(tocStart + newline)
+	buf.WriteString("## " + tocHeading + newline)
+	for _, h := range headings {
+		if h.level < 2 || h.level > 3 || h.text == "" {
+			continue
+		}
+		buf.WriteString(strings.Repeat("  ", h.level-2))
+		buf.WriteString("- [" + escapeLinkText(h.text) + "](#" + h.anchor + ")" + newline)
+	}
+	buf.WriteString(tocStop + newline)
+	return buf.String()
+}
+
+// escapeLinkText escapes the brackets of `s` not escaped yet, so that it
+// can be used as the text of a Markdown link.
+func escapeLinkText(s string) string {
+	buf := strings.Builder{}
+	escaped := false
+	for _, c := range s {
+		if (c == '[' || c == ']') && !escaped {
+			buf.WriteRune('\\')
+		}
+		escaped = c == '\\' && !escaped
+		buf.WriteRune(c)
+	}
+	return buf.String()
+}
+
+// AddTableOfContents adds a table of contents of the level 2 and 3 headings
+// to `markdown`. An existing table of contents is replaced, otherwise it is
+// inserted before the first level 2 heading. Documents without such a
+// heading are returned unchanged.
+func AddTableOfContents(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+
+	insert, fresh := -1, true
+	start, stop := -1, -1
+	for i, code := range codeLines(lines) {
+		if code {
+			continue
+		}
+		switch trimmed := strings.TrimSpace(lines[i]); {
+		case start == -1 && trimmed == tocStart:
+			start = i
+		case start != -1 && stop == -1 && trimmed == tocStop:
+			stop = i
+		}
+	}
+	if start != -1 && stop != -1 {
+		lines = append(lines[:start:start], lines[stop+1:]...)
+		insert, fresh = start, false
+	}
+
+	anchors := newAnchors()
+	headings := []markdownHeading{}
+	for i, code := range codeLines(lines) {
+		level, text, ok := parseHeading(lines[i])
+		if code || !ok {
+			continue
+		}
+		if insert == -1 && level >= 2 {
+			insert = i
+		}
+		if i == insert {
+			anchors.anchor(tocHeading)
+		}
+		headings = append(headings, markdownHeading{level, text, anchors.anchor(text)})
+	}
+	if insert == -1 {
+		return markdown
+	}
+
+	newline := "\n"
+	if strings.Contains(markdown, "\r\n") {
+		newline = "\r\n"
+	}
+	toc := strings.Split(strings.TrimSuffix(tableOfContents(headings, newline), "\n"), "\n")
+	if fresh {
+		if insert > 0 && strings.TrimSpace(lines[insert-1]) != "" {
+			toc = append([]string{strings.TrimSuffix(newline, "\n")}, toc...)
+		}
+		toc = append(toc, strings.TrimSuffix(newline, "\n"))
+	}
+
+	out := append(append(append([]string{}, lines[:insert]...), toc...), lines[insert:]...)
+	return strings.Join(out, "\n")
+}
+
+// parseHeading returns the level and text of the ATX heading `line`.
+func parseHeading(line string) (int, string, bool) {
+	m := atxHeadingPattern.FindStringSubmatch(strings.TrimSuffix(line, "\r"))
+	if m == nil {
+		return 0, "", false
+	}
+	return len(m[1]), m[2], true
+}
+
+// codeLines reports for each of the `lines` whether it belongs to a fenced
+// code block, including the fences.
+func codeLines(lines []string) []bool {
+	code := make([]bool, len(lines))
+	fence := ""
+	for i, line := range lines {
+		m := fencePattern.FindStringSubmatch(line)
+		switch {
+		case fence == "" && m != nil:
+			fence = m[1]
+		case fence != "" && m != nil && m[1][0] == fence[0] && len(m[1]) >= len(fence) &&
+			strings.TrimSpace(line[len(m[0]):]) == "":
+			code[i] = true
+			fence = ""
+			continue
+		}
+		code[i] = fence != ""
+	}
+	return code
+}
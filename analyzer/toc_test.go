@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMarkdownTableOfContents(t *testing.T) {
+	report := Report{
+		FilesAnalyzed: []string{"A.sol"},
+		Issues: []Issue{
+			{Identifier: "L-01", Severity: LOW, Title: "Use `a[i]` carefully"},
+			{Identifier: "CUSTOM-1", Severity: NC, Title: "Custom rule"},
+			{Identifier: "G-01", Severity: GASOP, Title: "No findings"},
+		},
+		FindingsPerIssue: map[string][]Finding{
+			"L-01":     {{File: "A.sol", LineNumber: 1, LineContent: "a"}},
+			"CUSTOM-1": {{File: "A.sol", LineNumber: 2, LineContent: "b"}},
+		},
+	}
+	for i := 100; i <= 101; i++ {
+		id := fmt.Sprintf("L-%d", i)
+		report.Issues = append(report.Issues, Issue{Identifier: id, Severity: LOW, Title: "Custom rule"})
+		report.FindingsPerIssue[id] = []Finding{{File: "A.sol", LineNumber: i, LineContent: "c"}}
+	}
+
+	md := report.Markdown(RenderOptions{ToC: true})
+	want := "# c4udit Report\n\n" +
+		"<!-- toc -->\n" +
+		"## Table of Contents\n" +
+		"- [Files analyzed](#files-analyzed)\n" +
+		"- [QA Issues found](#qa-issues-found)\n" +
+		"- [Low Findings](#low-findings)\n" +
+		"  - [\\[L-01\\] Use `a\\[i\\]` carefully](#l-01-use-ai-carefully)\n" +
+		"  - [\\[L-100\\] Custom rule](#l-100-custom-rule)\n" +
+		"  - [\\[L-101\\] Custom rule](#l-101-custom-rule)\n" +
+		"- [Non-Critical Findings](#non-critical-findings)\n" +
+		"  - [\\[CUSTOM-1\\] Custom rule](#custom-1-custom-rule)\n" +
+		"- [Gas Findings](#gas-findings)\n" +
+		"<!-- tocstop -->\n\n" +
+		"## Files analyzed\n"
+	if !strings.HasPrefix(md, want) {
+		t.Errorf("got\n%s\nwant prefix\n%s", md, want)
+	}
+
+	plain := report.Markdown(RenderOptions{})
+	if got := AddTableOfContents(plain); got != md {
+		t.Errorf("AddTableOfContents(report) differs from the rendered table of contents:\n%s", got)
+	}
+	if got := AddTableOfContents(md); got != md {
+		t.Errorf("refreshing the table of contents changed the report:\n%s", got)
+	}
+}
+
+func TestAddTableOfContents(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{
+			name: "insert",
+			in:   "# Report\nIntro\n## Setup\n```\n## not a heading\n```\n### Setup\n## Setup ##\n",
+			want: "# Report\nIntro\n\n" +
+				"<!-- toc -->\n## Table of Contents\n" +
+				"- [Setup](#setup)\n  - [Setup](#setup-1)\n- [Setup](#setup-2)\n" +
+				"<!-- tocstop -->\n\n" +
+				"## Setup\n```\n## not a heading\n```\n### Setup\n## Setup ##\n",
+		},
+		{
+			name: "refresh",
+			in:   "# Report\n\n<!-- toc -->\n## Table of Contents\n- [Old](#old)\n<!-- tocstop -->\n\n## New\n",
+			want: "# Report\n\n<!-- toc -->\n## Table of Contents\n- [New](#new)\n<!-- tocstop -->\n\n## New\n",
+		},
+		{
+			name: "heading named like the table of contents",
+			in:   "## Table of Contents\n",
+			want: "<!-- toc -->\n## Table of Contents\n- [Table of Contents](#table-of-contents-1)\n<!-- tocstop -->\n\n## Table of Contents\n",
+		},
+		{
+			name: "crlf",
+			in:   "# Report\r\n\r\n## A\r\n",
+			want: "# Report\r\n\r\n<!-- toc -->\r\n## Table of Contents\r\n- [A](#a)\r\n<!-- tocstop -->\r\n\r\n## A\r\n",
+		},
+		{
+			name: "no sections",
+			in:   "# Report\n#hashtag\n",
+			want: "# Report\n#hashtag\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := AddTableOfContents(tt.in); got != tt.want {
+			t.Errorf("%s: got\n%q\nwant\n%q", tt.name, got, tt.want)
+		}
+	}
+}
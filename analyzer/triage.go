@@ -0,0 +1,142 @@
+package analyzer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Triage is a CSV report edited by reviewers. Rows with the status "false
+// positive" or "fp" mark findings to drop from the report.
+type Triage struct {
+	Rows []TriageRow
+}
+
+// TriageRow is a row of a Triage.
+type TriageRow struct {
+	// Line is the line of the row in the CSV file.
+	Line        int
+	Issue       string
+	File        string
+	Fingerprint string
+	Status      string
+	Notes       string
+}
+
+// FalsePositive reports whether the row marks its finding as a false
+// positive.
+func (row TriageRow) FalsePositive() bool {
+	status := strings.ToLower(strings.TrimSpace(row.Status))
+	status = strings.NewReplacer("-", " ", "_", " ").Replace(status)
+	return status == "false positive" || status == "fp"
+}
+
+// ParseTriage reads a Triage in the CSV report format. Columns are found by
+// their header, so they can be reordered and other columns added. The `'`
+// that CSV prefixes to formula-like cells is removed.
+func ParseTriage(r io.Reader) (*Triage, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("missing header")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"id", "file", "status", "fingerprint"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	cell := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return unescapeCSVCell(strings.TrimSpace(record[i]))
+	}
+
+	triage := &Triage{Rows: []TriageRow{}}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		triage.Rows = append(triage.Rows, TriageRow{
+			Line:        line,
+			Issue:       cell(record, "id"),
+			File:        cell(record, "file"),
+			Fingerprint: cell(record, "fingerprint"),
+			Status:      cell(record, "status"),
+			Notes:       cell(record, "notes"),
+		})
+	}
+	return triage, nil
+}
+
+// LoadTriage reads a Triage from the CSV file at `path`.
+func LoadTriage(path string) (*Triage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	triage, err := ParseTriage(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid triage %s: %s", path, err)
+	}
+	return triage, nil
+}
+
+// ApplyTriage removes the findings marked as false positives in triage `t`
+// from report `r`. Findings are matched by their fingerprint, so that they
+// still match after lines moved, and each row drops at most one finding.
+// It returns the number of dropped findings and the false positive rows
+// that matched no finding.
+func (r *Report) ApplyTriage(t *Triage) (int, []TriageRow) {
+	rows := make(map[string][]TriageRow)
+	for _, row := range t.Rows {
+		if row.FalsePositive() {
+			rows[row.Fingerprint] = append(rows[row.Fingerprint], row)
+		}
+	}
+
+	dropped := 0
+	for id, findings := range r.FindingsPerIssue {
+		remaining := []Finding{}
+		for _, f := range findings {
+			fingerprint := r.Fingerprint(f)
+			if len(rows[fingerprint]) > 0 {
+				rows[fingerprint] = rows[fingerprint][1:]
+				dropped++
+				continue
+			}
+			remaining = append(remaining, f)
+		}
+		r.FindingsPerIssue[id] = remaining
+	}
+
+	unmatched := []TriageRow{}
+	for _, row := range t.Rows {
+		for _, left := range rows[row.Fingerprint] {
+			if left.Line == row.Line {
+				unmatched = append(unmatched, row)
+			}
+		}
+	}
+	return dropped, unmatched
+}
@@ -0,0 +1,144 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestTriage(t *testing.T) {
+	finding := func(line int, content string) Finding {
+		return Finding{
+			IssueIdentifier: "G-02",
+			File:            "src/A.sol",
+			Function:        "A.f",
+			LineNumber:      line,
+			Column:          9,
+			LineContent:     content,
+		}
+	}
+	content := `require(a > 0, "a, \"b\"");`
+	report := &Report{
+		Issues: []Issue{{Identifier: "G-02", Severity: GASOP, Title: "Use `!= 0`"}},
+		FindingsPerIssue: map[string][]Finding{
+			"G-02": {finding(10, content), finding(20, "require(b > 0);")},
+		},
+	}
+
+	data, err := report.CSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Commas and quotes of the line content survive the round trip.
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want header and 2 rows", len(records))
+	}
+	if records[1][6] != content || records[1][4] != "10" || records[1][5] != "9" {
+		t.Errorf("got row %q", records[1])
+	}
+
+	// A reviewer marks the first finding as a false positive and reorders
+	// the columns.
+	records[1][7] = "False Positive"
+	records[2][7] = "confirmed"
+	edited := bytes.Buffer{}
+	w := csv.NewWriter(&edited)
+	for _, record := range records {
+		w.Write(append(record[7:], record[:7]...))
+	}
+	w.Flush()
+
+	triage, err := ParseTriage(&edited)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triage.Rows) != 2 || !triage.Rows[0].FalsePositive() || triage.Rows[1].FalsePositive() {
+		t.Fatalf("got rows %+v", triage.Rows)
+	}
+
+	// Lines moved since the export.
+	report.FindingsPerIssue["G-02"] = []Finding{finding(12, content), finding(22, "require(b > 0);")}
+	dropped, unmatched := report.ApplyTriage(triage)
+	if dropped != 1 || len(unmatched) != 0 {
+		t.Errorf("got %d dropped and %d unmatched, want 1 and 0", dropped, len(unmatched))
+	}
+	findings := report.FindingsPerIssue["G-02"]
+	if len(findings) != 1 || findings[0].LineNumber != 22 {
+		t.Errorf("got findings %+v, want only the confirmed one", findings)
+	}
+
+	// The false positive is gone now.
+	_, unmatched = report.ApplyTriage(triage)
+	if len(unmatched) != 1 || unmatched[0].Line != 2 {
+		t.Errorf("got unmatched rows %+v, want row on line 2", unmatched)
+	}
+}
+
+func TestParseTriageErrors(t *testing.T) {
+	tests := map[string]string{
+		"":                                "missing header",
+		"id,file,status\n":                `missing column "fingerprint"`,
+		"id,file,status,fingerprint\n\"a": "extraneous or missing",
+	}
+	for input, want := range tests {
+		_, err := ParseTriage(strings.NewReader(input))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseTriage(%q): got error %v, want %q", input, err, want)
+		}
+	}
+}
+
+func TestFalsePositive(t *testing.T) {
+	for status, want := range map[string]bool{
+		"false positive": true,
+		"False-Positive": true,
+		" FP ":           true,
+		"":               false,
+		"confirmed":      false,
+	} {
+		if got := (TriageRow{Status: status}).FalsePositive(); got != want {
+			t.Errorf("%q: got %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestCSVFormulaCells(t *testing.T) {
+	report := &Report{
+		Issues: []Issue{{Identifier: "G-02", Severity: GASOP, Title: "=HYPERLINK(\"x\")"}},
+		FindingsPerIssue: map[string][]Finding{
+			"G-02": {{File: "@src/A.sol", LineNumber: 1, LineContent: "- amount;"}},
+		},
+	}
+	data, err := report.CSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := records[1]
+	if row[2] != "'=HYPERLINK(\"x\")" || row[3] != "'@src/A.sol" || row[6] != "'- amount;" {
+		t.Errorf("got row %q, want formula-like cells prefixed with '", row)
+	}
+
+	triage, err := ParseTriage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := triage.Rows[0].File; got != "@src/A.sol" {
+		t.Errorf("got file %q, want the prefix removed", got)
+	}
+
+	for _, s := range []string{"=1+1", "+x", "'quoted", "'=x", "plain", "'"} {
+		if got := unescapeCSVCell(csvCell(s)); got != s {
+			t.Errorf("%q: got %q after round trip", s, got)
+		}
+	}
+}
@@ -2,21 +2,53 @@ package analyzer
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 // Report is the end result of an analysis containing the files analyzed,
-// the issues searched for and a map of findings per issue.
+// the issues searched for, a map of findings per issue, the paths that
+// could not be analyzed and the suppression comments found.
+// All paths in the report are relative to Root.
 type Report struct {
+	Metadata      Metadata
+	Root          string
 	Issues        []Issue
 	FilesAnalyzed []string
 	// Key is Issue Identifier
 	FindingsPerIssue map[string][]Finding
+	Errors           []AnalysisError
+	Suppressions     []Suppression
+	// Key is Issue Identifier
+	SuppressedPerIssue map[string]int
+	// Baseline is set if findings were compared against a baseline.
+	Baseline *BaselineStats
+	// Scope is the scope file the analysis was restricted to, if any.
+	Scope string
+	// ProjectRoot is the git working tree containing Root, or Root. Paths in
+	// fingerprints are relative to it.
+	ProjectRoot string
+}
+
+// Metadata describes a Report. Empty fields are omitted from the output.
+type Metadata struct {
+	// Title defaults to "c4udit Report".
+	Title   string `json:"title,omitempty" yaml:"title,omitempty"`
+	Project string `json:"project,omitempty" yaml:"project,omitempty"`
+	Author  string `json:"author,omitempty" yaml:"author,omitempty"`
+}
+
+// AnalysisError is an error that prevented a path from being analyzed.
+type AnalysisError struct {
+	Path    string
+	Message string
 }
 
 // Issue represents an Issue to search for in the codebase.
 // The pattern field is a RegEx string which must compile.
+// The scope field defines which parts of the source the pattern is matched
+// against.
+// If the detector field is set, it is used instead of the pattern.
 type Issue struct {
 	Identifier     string
 	Severity       Severity
@@ -24,14 +56,66 @@ type Issue struct {
 	Impact         string
 	Pattern        string
 	Recommendation string
+	Scope          Scope
+	Detector       Detector
+
+	// ExcludePatterns drop findings whose line content matches any of them.
+	ExcludePatterns []string
+	// Files restricts the Issue to files matching any of the globs, see
+	// MatchGlob. All files are checked if empty.
+	Files []string
+	// Source is the rule file the Issue was loaded from, empty for built-in
+	// Issues.
+	Source string
+}
+
+// Detector is a check implemented in Go for Issues that can not be expressed
+// as a single RegEx. It is run once per source file.
+type Detector interface {
+	// Detect returns the findings in `file`. The findings' IssueIdentifier
+	// is set by the analyzer.
+	Detect(file *SourceFile) []Finding
+}
+
+// DetectorFunc is an adapter to allow the use of ordinary functions as
+// Detectors.
+type DetectorFunc func(file *SourceFile) []Finding
+
+// Detect calls f(file).
+func (f DetectorFunc) Detect(file *SourceFile) []Finding {
+	return f(file)
 }
 
 // Finding represents a possible Issue found in the codebase.
+// A Finding may span several lines, from LineNumber to EndLineNumber.
+// Columns are 1-based character positions, EndColumn is the column just
+// after the match on EndLineNumber.
 type Finding struct {
 	IssueIdentifier string
 	File            string
 	LineNumber      int
-	LineContent     string
+	EndLineNumber   int
+	Column          int
+	EndColumn       int
+	// Match is the matched source text.
+	Match       string
+	LineContent string
+	// Function is the enclosing function as `Contract.function`, empty at
+	// contract or file level.
+	Function string
+}
+
+// RenderOptions configures how a Report is rendered.
+type RenderOptions struct {
+	// ToC adds tables of contents to Markdown reports.
+	ToC bool
+	// Carets underlines the match of each single-line finding.
+	Carets bool
+	// Workspace is the directory GitHub annotations are relative to.
+	Workspace string
+	// Repository links the findings of Markdown reports to their source,
+	// if set.
+	Repository *Repository
 }
 
 // Severity type defining the severity level for an Issue.
@@ -44,49 +128,25 @@ const (
 	LOW
 )
 
-func slugify(s string) string {
-	droppedChars := []string{
-		"\"", "'", "`", ".", "/",
-		"!", ",", "~", "&",
-		"%", "^", "*", "#",
-		"@", "|",
-		"(", ")",
-		"{", "}",
-		"[", "]", "<", ">", "++", "==", "+", "=",
-	}
+// Scope type defining which parts of a source file an Issue's pattern
+// applies to.
+type Scope int
 
-	s = strings.ToLower(s)
-	// output still is L01, need to add dash so its L-01
-	for _, c := range droppedChars {
-		s = strings.Replace(s, c, "", -1)
-	}
-
-	s = strings.Replace(s, " ", "-", -1)
-
-	// fmt.Println("--------------------------------")
-	// fmt.Println(s)
-
-	// s = strings.Replace(s, "--", "-", -1)
-	m1 := regexp.MustCompile(`[a-z]-[0-9][0-9]--`)
-	m2 := regexp.MustCompile(`[a-z]-[0-9][0-9]`)
-	num := m2.Find([]byte(s))
-	// fmt.Println(num)
-	s = m1.ReplaceAllString(s, string(num)+"-")
-	// fmt.Println(s)
-
-	// fmt.Println("--------------------------------")
-
-	return s
-}
-
-func createLink(identifier string, title string) string {
-	link := fmt.Sprintf("#%s -%s", identifier, title)
-	link = slugify(link)
-	return "#" + link
-}
+// The Scope Enum.
+//
+// CODE matches against source code with comments and string literal contents
+// blanked out. COMMENTS matches against comments only. STRINGS matches against
+// source code including string literals, but only if the match touches a
+// string literal.
+const (
+	CODE Scope = iota
+	COMMENTS
+	STRINGS
+)
 
-// Markdown returns the report as string in markdown style.
-func (r Report) Markdown(toc bool) string {
+// Markdown returns the report as string in markdown style. With opts.ToC, a
+// table of contents linking the sections and issues follows the title.
+func (r Report) Markdown(opts RenderOptions) string {
 	// Issue output in Code4Rena format:
 	// ### {{ issue.Title }}
 	//
@@ -94,7 +154,7 @@ func (r Report) Markdown(toc bool) string {
 	// Issue information: [{{ issue.Identifier }}]({{ issue.Impact }})
 	//
 	// #### Findings
-	// {{ _, finding := range findings: finding.String() }}
+	// {{ _, finding := range findings: finding.render(opts.Carets) }}
 	//
 	// #### Recommendation
 	// {{issue.Recommendation}}
@@ -102,218 +162,145 @@ func (r Report) Markdown(toc bool) string {
 	// #### Tools used
 	//
 
-	buf := strings.Builder{}
+	buf := newMarkdownWriter()
 
-	buf.WriteString("# c4udit Report\n")
+	buf.heading(1, r.Metadata.title())
 	buf.WriteString("\n")
+	if r.Metadata.Project != "" {
+		buf.WriteString("Project: " + r.Metadata.Project + "\n")
+	}
+	if r.Metadata.Author != "" {
+		buf.WriteString("Author: " + r.Metadata.Author + "\n")
+	}
+	if r.Metadata.Project != "" || r.Metadata.Author != "" {
+		buf.WriteString("\n")
+	}
+
+	// The table of contents is inserted here once all headings are known.
+	tocOffset := buf.Len()
+	if opts.ToC {
+		buf.reserve(tocHeading)
+	}
 
-	buf.WriteString("## Files analyzed\n")
+	buf.heading(2, "Files analyzed")
 	for _, f := range r.FilesAnalyzed {
 		buf.WriteString("- " + f + "\n")
 	}
 
-	//low and Non-Critical
-	//links
-
-	//low
-	if toc {
-		buf.WriteString("# Table of Contents \n")
-
-		canWriteLowTitle := true
-		for _, issue := range r.Issues {
+	if len(r.Errors) > 0 {
+		buf.WriteString("\n")
+		buf.heading(2, "Warnings")
+		buf.WriteString("The following paths could not be analyzed:\n")
+		for _, e := range r.Errors {
+			buf.WriteString("- " + e.Error() + "\n")
+		}
+		buf.WriteString("\n")
+	}
 
-			findings := r.FindingsPerIssue[issue.Identifier]
-			if len(findings) == 0 {
-				continue
-			}
-			if issue.Severity == LOW {
-				if canWriteLowTitle {
-					buf.WriteString("Low\n")
-				}
-				link := createLink(issue.Identifier, issue.Title)
-				buf.WriteString("- [[" + issue.Identifier + "] " + issue.Title + "](" + link + ")\n")
-				canWriteLowTitle = false
-			} else {
-				continue
-			}
+	if r.Scope != "" {
+		buf.WriteString("\n")
+		buf.heading(2, "Scope")
+		buf.WriteString("Only the files listed in " + r.Scope + " were analyzed.\n")
+		buf.WriteString("\n")
+	}
 
-		}
+	if r.Baseline != nil {
+		buf.WriteString("\n")
+		buf.heading(2, "Baseline")
+		buf.WriteString(fmt.Sprintf("- %d known findings hidden\n", r.Baseline.Known))
+		buf.WriteString(fmt.Sprintf("- %d baseline findings no longer found\n", r.Baseline.Stale))
+		buf.WriteString("\n")
+	}
 
-		//Nc
-		canWriteNctitle := true
+	if len(r.SuppressedPerIssue) > 0 {
+		buf.WriteString("\n")
+		buf.heading(2, "Suppressed findings")
 		for _, issue := range r.Issues {
-
-			findings := r.FindingsPerIssue[issue.Identifier]
-			if len(findings) == 0 {
-				continue
-			}
-			if issue.Severity == NC {
-				if canWriteNctitle {
-					buf.WriteString("\nNon-Critical\n")
-				}
-				link := createLink(issue.Identifier, issue.Title)
-				buf.WriteString("- [[" + issue.Identifier + "] " + issue.Title + "](" + link + ")\n")
-				canWriteNctitle = false
-			} else {
-				continue
+			if count := r.SuppressedPerIssue[issue.Identifier]; count > 0 {
+				buf.WriteString(fmt.Sprintf("- [%s] %s: %d\n", issue.Identifier, issue.Title, count))
 			}
-
 		}
-
 		buf.WriteString("\n")
-
 	}
-	buf.WriteString("## QA Issues found\n")
-	buf.WriteString("\n")
-	//list  low and nc
-	buf.WriteString("## Low Findings\n")
-	buf.WriteString("\n")
-	for _, issue := range r.Issues {
-		findings := r.FindingsPerIssue[issue.Identifier]
-		if len(findings) == 0 {
-			continue
-		}
-		if issue.Severity == GASOP {
-			continue
-		}
 
-		if issue.Severity == NC {
-			continue
-		}
-
-		buf.WriteString("### [" + issue.Identifier + "] " + issue.Title + "\n")
-
-		// Impact
-		// if issue.Severity == NC {
-		if issue.Impact != "" {
-			buf.WriteString("#### Impact\n")
-			buf.WriteString(issue.Impact + "\n")
-		}
-
-		// Findings
-		buf.WriteString("#### Findings:\n")
-		buf.WriteString("```solidity\n")
-		for _, finding := range findings {
-			buf.WriteString(finding.String())
-		}
-		buf.WriteString("```\n")
-
-		// Recommendation
-		buf.WriteString("#### Recommendation\n")
-		buf.WriteString(issue.Recommendation + "\n")
+	if r.hasSeverity(LOW) || r.hasSeverity(NC) {
+		buf.heading(2, "QA Issues found")
 		buf.WriteString("\n")
 	}
-
-	buf.WriteString("## Non-Critical Findings\n")
-	buf.WriteString("\n")
-	for _, issue := range r.Issues {
-		findings := r.FindingsPerIssue[issue.Identifier]
-		if len(findings) == 0 {
-			continue
-		}
-		if issue.Severity == GASOP {
-			continue
-		}
-
-		if issue.Severity == LOW {
-			continue
-		}
-
-		buf.WriteString("### [" + issue.Identifier + "] " + issue.Title + "\n")
-
-		// Impact
-		// if issue.Severity == NC {
-		if issue.Impact != "" {
-			buf.WriteString("#### Impact\n")
-			buf.WriteString(issue.Impact + "\n")
-		}
-
-		// Findings
-		buf.WriteString("#### Findings:\n")
-		buf.WriteString("```solidity\n")
-		for _, finding := range findings {
-			buf.WriteString(finding.String())
-		}
-		buf.WriteString("```\n")
-
-		// Recommendation
-		buf.WriteString("#### Recommendation\n")
-		buf.WriteString(issue.Recommendation + "\n")
+	if r.hasSeverity(LOW) {
+		buf.heading(2, "Low Findings")
 		buf.WriteString("\n")
 	}
+	r.markdownIssues(buf, LOW, opts)
 
-	///gas
-
-	if toc {
-
-		buf.WriteString("# Table of Contents \n")
+	if r.hasSeverity(NC) {
+		buf.heading(2, "Non-Critical Findings")
+		buf.WriteString("\n")
+	}
+	r.markdownIssues(buf, NC, opts)
 
-		canWriteGasTitle := true
-		for _, issue := range r.Issues {
+	if r.hasSeverity(GASOP) {
+		buf.heading(2, "Gas Findings")
+		buf.WriteString("\n")
+	}
+	r.markdownIssues(buf, GASOP, opts)
 
-			findings := r.FindingsPerIssue[issue.Identifier]
-			if len(findings) == 0 {
-				continue
-			}
-			if issue.Severity == GASOP {
-				if canWriteGasTitle {
-					buf.WriteString("Gas\n")
-				}
-				link := createLink(issue.Identifier, issue.Title)
-				buf.WriteString("- [[" + issue.Identifier + "] " + issue.Title + "](" + link + ")\n")
-				canWriteGasTitle = false
-			} else {
-				continue
-			}
+	// Tools used
+	buf.heading(4, "Tools used")
+	buf.WriteString("manual, c4udit, slither" + "\n")
 
-		}
+	buf.WriteString("\n")
 
-		buf.WriteString("\n")
+	out := buf.String()
+	if !opts.ToC {
+		return out
 	}
+	return out[:tocOffset] + tableOfContents(buf.headings, "\n") + "\n" + out[tocOffset:]
+}
 
-	buf.WriteString("## Gas Findings\n")
-	buf.WriteString("\n")
-	//list  gas
+// markdownIssues writes the Issues of severity `s` that have findings.
+func (r Report) markdownIssues(buf *markdownWriter, s Severity, opts RenderOptions) {
 	for _, issue := range r.Issues {
 		findings := r.FindingsPerIssue[issue.Identifier]
-		if len(findings) == 0 {
-			continue
-		}
-		if issue.Severity != GASOP {
+		if len(findings) == 0 || issue.Severity != s {
 			continue
 		}
 
-		buf.WriteString("### [" + issue.Identifier + "] " + issue.Title + "\n")
+		buf.heading(3, "["+issue.Identifier+"] "+issue.Title)
 
 		// Impact
 		if issue.Impact != "" {
-			buf.WriteString("#### Impact\n")
+			buf.heading(4, "Impact")
 			buf.WriteString(issue.Impact + "\n")
 		}
+
 		// Findings
-		buf.WriteString("#### Findings:\n")
-		buf.WriteString("```solidity\n")
-		for _, finding := range findings {
-			buf.WriteString(finding.String())
-		}
-		buf.WriteString("```\n")
+		buf.heading(4, "Findings:")
+		buf.WriteString(r.markdownFindings(findings, opts))
 
 		// Recommendation
-		buf.WriteString("#### Recommendation\n")
+		buf.heading(4, "Recommendation")
 		buf.WriteString(issue.Recommendation + "\n")
 		buf.WriteString("\n")
 	}
+}
 
-	// Tools used
-	buf.WriteString("#### Tools used\n")
-	buf.WriteString("manual, c4udit, slither" + "\n")
-
-	buf.WriteString("\n")
-
-	return buf.String()
+// hasSeverity reports whether any Issue of the report has severity `s`.
+func (r Report) hasSeverity(s Severity) bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == s {
+			return true
+		}
+	}
+	return false
 }
 
 func (r Report) String() string {
+	return r.Text(RenderOptions{})
+}
+
+// Text returns the report as plain text.
+func (r Report) Text(opts RenderOptions) string {
 	// Build files string.
 	files := "Files analyzed:\n"
 	for _, f := range r.FilesAnalyzed {
@@ -321,6 +308,41 @@ func (r Report) String() string {
 	}
 	files += "\n"
 
+	// Build warnings string.
+	if len(r.Errors) > 0 {
+		files += "Warnings:\n"
+		for _, e := range r.Errors {
+			files += fmt.Sprintf("- %s\n", e.Error())
+		}
+		files += "\n"
+	}
+
+	// Build scope string.
+	if r.Scope != "" {
+		files += "Scope:\n"
+		files += fmt.Sprintf("- only the files listed in %s were analyzed\n", r.Scope)
+		files += "\n"
+	}
+
+	// Build baseline string.
+	if r.Baseline != nil {
+		files += "Baseline:\n"
+		files += fmt.Sprintf("- %d known findings hidden\n", r.Baseline.Known)
+		files += fmt.Sprintf("- %d baseline findings no longer found\n", r.Baseline.Stale)
+		files += "\n"
+	}
+
+	// Build suppressed findings string.
+	if len(r.SuppressedPerIssue) > 0 {
+		files += "Suppressed findings:\n"
+		for _, issue := range r.Issues {
+			if count := r.SuppressedPerIssue[issue.Identifier]; count > 0 {
+				files += fmt.Sprintf("- [%s] %d\n", issue.Identifier, count)
+			}
+		}
+		files += "\n"
+	}
+
 	// Build issues string.
 	issues := "Issues found:\n"
 	for i, issue := range r.Issues {
@@ -335,7 +357,7 @@ func (r Report) String() string {
 		// Add findings per issue
 		issues += "[" + issue.Identifier + "] " + issue.Title + ":\n"
 		for _, finding := range findings {
-			issues += "  " + finding.String()
+			issues += indent(finding.render(opts.Carets), "  ")
 		}
 
 		// Add newline if not last issue
@@ -351,8 +373,107 @@ func (i Issue) String() string {
 	return i.Identifier
 }
 
+// UnusedSuppressions returns the suppression comments that did not suppress
+// any finding.
+func (r Report) UnusedSuppressions() []Suppression {
+	unused := []Suppression{}
+	for _, s := range r.Suppressions {
+		if s.Count == 0 {
+			unused = append(unused, s)
+		}
+	}
+	return unused
+}
+
+func (e AnalysisError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
 func (f Finding) String() string {
-	return fmt.Sprintf("%s::%d => %s\n", f.File, f.LineNumber, f.LineContent)
+	return f.location() + f.LineContent + "\n"
+}
+
+// location returns the prefix of the finding's string representation.
+func (f Finding) location() string {
+	if f.EndLineNumber > f.LineNumber {
+		return fmt.Sprintf("%s::%d-%d => ", f.File, f.LineNumber, f.EndLineNumber)
+	}
+	return fmt.Sprintf("%s::%d => ", f.File, f.LineNumber)
+}
+
+// render returns the finding's string representation, optionally followed
+// by a line underlining the match with carets.
+func (f Finding) render(carets bool) string {
+	return f.renderAfter(f.location(), carets)
+}
+
+// renderAfter returns the finding's line content after `prefix`, optionally
+// followed by a line underlining the match with carets.
+func (f Finding) renderAfter(prefix string, carets bool) string {
+	line := prefix + f.LineContent + "\n"
+	if !carets || f.Match == "" || f.EndLineNumber > f.LineNumber {
+		return line
+	}
+
+	// LineContent is trimmed, so the match is at most at its column.
+	content := []rune(f.LineContent)
+	match := []rune(f.Match)
+	offset := f.Column - 1
+	if offset > len(content)-len(match) {
+		offset = len(content) - len(match)
+	}
+	for ; offset >= 0; offset-- {
+		if string(content[offset:offset+len(match)]) == f.Match {
+			break
+		}
+	}
+	if offset < 0 {
+		return line
+	}
+
+	padding := utf8.RuneCountInString(prefix) + offset
+	return line + strings.Repeat(" ", padding) + strings.Repeat("^", len(match)) + "\n"
+}
+
+// markdownFindings returns the findings of an issue in a Markdown report.
+// With a repository, every finding in it links to its source.
+func (r Report) markdownFindings(findings []Finding, opts RenderOptions) string {
+	buf := strings.Builder{}
+	if opts.Repository == nil {
+		buf.WriteString("```solidity\n")
+		for _, finding := range findings {
+			buf.WriteString(finding.render(opts.Carets))
+		}
+		buf.WriteString("```\n")
+		return buf.String()
+	}
+
+	for _, finding := range findings {
+		lines := fmt.Sprintf("#L%d", finding.LineNumber)
+		if finding.EndLineNumber > finding.LineNumber {
+			lines += fmt.Sprintf("-L%d", finding.EndLineNumber)
+		}
+		if link := r.permalink(opts.Repository, finding); link != "" {
+			buf.WriteString(fmt.Sprintf("[%s%s](%s)\n", finding.File, lines, link))
+		} else {
+			buf.WriteString(finding.File + lines + "\n")
+		}
+		buf.WriteString("```solidity\n")
+		buf.WriteString(finding.renderAfter("", opts.Carets))
+		buf.WriteString("```\n")
+	}
+	return buf.String()
+}
+
+// indent prefixes every line of `s` with `prefix`.
+func indent(s string, prefix string) string {
+	lines := strings.SplitAfter(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "")
 }
 
 func (s Severity) String() string {
@@ -362,3 +483,48 @@ func (s Severity) String() string {
 		"Low Risk",
 	}[s]
 }
+
+// ParseSeverity returns the Severity named `s`, which is either one of
+// "gas", "nc" and "low" or the severity's String().
+func ParseSeverity(s string) (Severity, error) {
+	for _, severity := range []Severity{GASOP, NC, LOW} {
+		if strings.EqualFold(s, severity.String()) || strings.EqualFold(s, severity.shortName()) {
+			return severity, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown severity %q", s)
+}
+
+// shortName returns the name of the severity used on the command line.
+func (s Severity) shortName() string {
+	return []string{
+		"gas",
+		"nc",
+		"low",
+	}[s]
+}
+
+func (m Metadata) title() string {
+	if m.Title == "" {
+		return "c4udit Report"
+	}
+	return m.Title
+}
+
+// ParseScope returns the Scope named `s`.
+func ParseScope(s string) (Scope, error) {
+	for _, scope := range []Scope{CODE, COMMENTS, STRINGS} {
+		if strings.EqualFold(s, scope.String()) {
+			return scope, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown scope %q", s)
+}
+
+func (s Scope) String() string {
+	return []string{
+		"code",
+		"comments",
+		"strings",
+	}[s]
+}
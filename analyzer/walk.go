@@ -0,0 +1,269 @@
+package analyzer
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultExcludes are the files and directories not analyzed by default:
+// dependencies, build output, tests, mocks and scripts. They are relative to
+// the project root, so that e.g. src/lib/ is still analyzed.
+var DefaultExcludes = []string{
+	".git/",
+	"node_modules/",
+	"/lib/",
+	"/out/",
+	"/cache/",
+	"/artifacts/",
+	"/test/",
+	"/tests/",
+	"mock/",
+	"mocks/",
+	"/script/",
+	"/scripts/",
+	"*.t.sol",
+	"*.s.sol",
+}
+
+// ignoreRule is a pattern of a .gitignore file in directory `dir`.
+type ignoreRule struct {
+	dir     string
+	pattern string
+	negate  bool
+}
+
+// walker collects the Solidity files in directories.
+type walker struct {
+	report   *Report
+	opts     Options
+	excludes []string
+	defaults []string
+	files    []string
+}
+
+// collectFiles returns the Solidity files in `paths`. Files found in
+// directories are skipped if they or one of their parent directories match
+// an exclude pattern, the default excludes or a .gitignore rule, or if they
+// do not match an include pattern. If opts.Scope is set, only the files of
+// the scope in `paths` are returned. Paths that can not be read are added
+// to the report's errors.
+func collectFiles(report *Report, paths []string, opts Options) []string {
+	if opts.Scope != nil {
+		return scopedFiles(paths, opts.Scope)
+	}
+
+	w := &walker{
+		report:   report,
+		opts:     opts,
+		excludes: opts.Exclude,
+		files:    []string{},
+	}
+	if !opts.NoDefaultExcludes {
+		w.defaults = DefaultExcludes
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			report.Errors = append(report.Errors, report.newAnalysisError(path, err))
+			continue
+		}
+
+		if !info.IsDir() {
+			// Files given as paths are always analyzed.
+			if strings.HasSuffix(path, ".sol") {
+				w.files = append(w.files, path)
+			}
+			continue
+		}
+
+		rules := []ignoreRule{}
+		if !opts.NoGitignore {
+			rules = parentIgnoreRules(path)
+		}
+		w.walk(path, rules)
+	}
+
+	return w.files
+}
+
+// walk collects the files in directory `dir`, ignoring the files matching
+// `rules`.
+func (w *walker) walk(dir string, rules []ignoreRule) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		w.report.Errors = append(w.report.Errors, w.report.newAnalysisError(dir, err))
+		return
+	}
+
+	if !w.opts.NoGitignore {
+		// Copy the rules, so that siblings do not share them.
+		rules = append(rules[:len(rules):len(rules)], readIgnoreRules(dir)...)
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		isDir := entry.IsDir()
+		if w.excluded(path, isDir) || ignored(rules, path, isDir) {
+			continue
+		}
+
+		if isDir {
+			w.walk(path, rules)
+			continue
+		}
+		if !strings.HasSuffix(path, ".sol") {
+			continue
+		}
+		if len(w.opts.Include) > 0 && !matchAny(w.opts.Include, w.report.relativePath(path)) {
+			continue
+		}
+		w.files = append(w.files, path)
+	}
+}
+
+// excluded reports whether `path` matches an exclude pattern or, relative
+// to the project root, a default exclude.
+func (w *walker) excluded(path string, isDir bool) bool {
+	rel := w.report.relativePath(path)
+	for _, pattern := range w.excludes {
+		if matchEntry(pattern, rel, isDir) {
+			return true
+		}
+	}
+	if len(w.defaults) == 0 {
+		return false
+	}
+	rel = w.report.projectPath(rel)
+	for _, pattern := range w.defaults {
+		if matchEntry(pattern, rel, isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchEntry reports whether the file or directory at slash-separated `rel`
+// matches the exclude `pattern`. Like in .gitignore files, patterns ending
+// in a slash only match directories, and patterns without a slash match the
+// name of the entry. Other patterns match the whole path.
+func matchEntry(pattern string, rel string, isDir bool) bool {
+	if strings.HasSuffix(pattern, "/") {
+		if !isDir {
+			return false
+		}
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	if strings.HasPrefix(pattern, "./") {
+		pattern = pattern[1:]
+	}
+
+	if !strings.Contains(pattern, "/") {
+		return globRegexp(pattern).MatchString(path.Base(rel))
+	}
+	return globRegexp(strings.TrimPrefix(pattern, "/")).MatchString(rel)
+}
+
+// ignored reports whether `path` is ignored by `rules`. The last matching
+// rule decides.
+func ignored(rules []ignoreRule, path string, isDir bool) bool {
+	if len(rules) == 0 {
+		return false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+
+	ignored := false
+	for _, rule := range rules {
+		rel, err := filepath.Rel(rule.dir, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if matchEntry(rule.pattern, filepath.ToSlash(rel), isDir) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// readIgnoreRules returns the rules of the .gitignore file in `dir`, if any.
+func readIgnoreRules(dir string) []ignoreRule {
+	content, err := ioutil.ReadFile(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return nil
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+
+	rules := []ignoreRule{}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimRight(line, " \r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{dir: dir}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, "\\")
+		// A slash at the start or in the middle anchors the pattern to dir.
+		if strings.Contains(strings.TrimSuffix(line, "/"), "/") && !strings.HasPrefix(line, "/") {
+			line = "/" + line
+		}
+		rule.pattern = line
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// parentIgnoreRules returns the rules of the .gitignore files in the parent
+// directories of `dir` within its git repository.
+func parentIgnoreRules(dir string) []ignoreRule {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+
+	if _, err := os.Stat(filepath.Join(abs, ".git")); err == nil {
+		// dir is the repository root.
+		return nil
+	}
+
+	// Find the parents up to the repository root.
+	parents := []string{}
+	for current := filepath.Dir(abs); ; current = filepath.Dir(current) {
+		parents = append(parents, current)
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			break
+		}
+		if filepath.Dir(current) == current {
+			// Not in a git repository.
+			return nil
+		}
+	}
+
+	rules := []ignoreRule{}
+	for i := len(parents) - 1; i >= 0; i-- {
+		rules = append(rules, readIgnoreRules(parents[i])...)
+	}
+	return rules
+}
+
+// matchAny reports whether `path` matches any of the globs `patterns`.
+func matchAny(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if MatchGlob(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestListFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{
+		"src/A.sol",
+		"src/AMock.sol",
+		"src/legacy/B.sol",
+		"src/lib/X.sol",
+		"src/test/Y.sol",
+		"src/README.md",
+		"src/A.t.sol",
+		"lib/forge-std/src/Test.sol",
+		"node_modules/@openzeppelin/ERC20.sol",
+		"test/A.t.sol",
+		"script/Deploy.s.sol",
+		"mocks/M.sol",
+		"gen/G.sol",
+		"gen/Keep.sol",
+	}
+	for _, file := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte("contract A {}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, ".gitignore"), []byte("# generated\ngen/*\n!gen/Keep.sol\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		paths []string
+		opts  Options
+		want  []string
+	}{
+		{
+			"defaults",
+			[]string{dir},
+			Options{Root: dir},
+			[]string{"gen/Keep.sol", "src/A.sol", "src/AMock.sol", "src/legacy/B.sol", "src/lib/X.sol", "src/test/Y.sol"},
+		},
+		{
+			// Default excludes are relative to the project root, not to
+			// the analyzed directory.
+			"nested",
+			[]string{filepath.Join(dir, "src")},
+			Options{},
+			[]string{"A.sol", "AMock.sol", "legacy/B.sol", "lib/X.sol", "test/Y.sol"},
+		},
+		{
+			"excludes",
+			[]string{dir},
+			Options{Root: dir, Exclude: []string{"src/legacy/", "*Mock*.sol", "src/*/?.sol"}},
+			[]string{"gen/Keep.sol", "src/A.sol"},
+		},
+		{
+			"includes",
+			[]string{dir},
+			Options{Root: dir, Include: []string{"src/*.sol"}},
+			[]string{"src/A.sol", "src/AMock.sol"},
+		},
+		{
+			// .gitignore files of parent directories apply.
+			"subdirectory",
+			[]string{filepath.Join(dir, "gen")},
+			Options{Root: dir},
+			[]string{"gen/Keep.sol"},
+		},
+		{
+			// Directories and files given as paths are not excluded.
+			"explicit paths",
+			[]string{filepath.Join(dir, "mocks"), filepath.Join(dir, "test", "A.t.sol")},
+			Options{Root: dir},
+			[]string{"mocks/M.sol", "test/A.t.sol"},
+		},
+		{
+			"no excludes",
+			[]string{dir},
+			Options{Root: dir, NoDefaultExcludes: true, NoGitignore: true},
+			[]string{
+				"gen/G.sol", "gen/Keep.sol", "lib/forge-std/src/Test.sol", "mocks/M.sol",
+				"node_modules/@openzeppelin/ERC20.sol", "script/Deploy.s.sol", "src/A.sol",
+				"src/A.t.sol", "src/AMock.sol", "src/legacy/B.sol", "src/lib/X.sol",
+				"src/test/Y.sol", "test/A.t.sol",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got, errs, err := ListFiles(test.paths, test.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(errs) > 0 {
+			t.Errorf("%s: unexpected errors: %v", test.name, errs)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got files %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/byterocket/c4udit/analyzer"
+	"gopkg.in/yaml.v3"
+)
+
+// configFileNames are the names of config files, in order of precedence.
+var configFileNames = []string{".c4udit.json", ".c4udit.yaml", ".c4udit.yml"}
+
+// Config is the project configuration. It is read from the first config file
+// found in the working directory or one of its parents. Command line flags
+// override it.
+type Config struct {
+	// Root is the project root, relative to the config file. Defaults to
+	// the directory of the config file.
+	Root   string `json:"root,omitempty" yaml:"root,omitempty"`
+	Jobs   int    `json:"jobs,omitempty" yaml:"jobs,omitempty"`
+	Strict bool   `json:"strict,omitempty" yaml:"strict,omitempty"`
+
+	// Globs of files to analyze and to skip, see analyzer.Options.
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+	// Disable analyzer.DefaultExcludes and .gitignore files.
+	NoDefaultExcludes bool `json:"noDefaultExcludes,omitempty" yaml:"noDefaultExcludes,omitempty"`
+	NoGitignore       bool `json:"noGitignore,omitempty" yaml:"noGitignore,omitempty"`
+	// Scope is a scope.txt or README.md listing the files to analyze, see
+	// analyzer.LoadScope. Defaults to the scope table of the contest's
+	// README.md, unless NoScope is set.
+	Scope   string `json:"scope,omitempty" yaml:"scope,omitempty"`
+	NoScope bool   `json:"noScope,omitempty" yaml:"noScope,omitempty"`
+
+	// Rule files with additional issues, see analyzer.LoadRules.
+	Rules []string `json:"rules,omitempty" yaml:"rules,omitempty"`
+
+	// Issue identifiers to run, with wildcards like "G-*". If Enable is
+	// set, only those issues run.
+	Enable  []string `json:"enable,omitempty" yaml:"enable,omitempty"`
+	Disable []string `json:"disable,omitempty" yaml:"disable,omitempty"`
+	// Severities to run, e.g. "gas". All severities run if empty.
+	Severities []string `json:"severities,omitempty" yaml:"severities,omitempty"`
+	// Severity overrides per issue identifier, e.g. "G-06": "nc".
+	Severity map[string]string `json:"severity,omitempty" yaml:"severity,omitempty"`
+
+	// Outputs to write. Defaults to text on stdout.
+	Outputs []Output          `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+	Report  analyzer.Metadata `json:"report,omitempty" yaml:"report,omitempty"`
+
+	// File is the config file read, empty if none.
+	File string `json:"file,omitempty" yaml:"-"`
+}
+
+// Output is a report output. An empty path or "-" is stdout.
+type Output struct {
+	Format string `json:"format" yaml:"format"`
+	Path   string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+// loadConfig reads the config file at `path`, or discovers it from the
+// working directory upward if `path` is empty. Without a config file the
+// zero Config is returned.
+func loadConfig(path string) (*Config, error) {
+	if path == "" {
+		var err error
+		path, err = findConfig()
+		if err != nil || path == "" {
+			return &Config{}, err
+		}
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		err = decoder.Decode(cfg)
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid config %s: %s", path, err)
+	}
+
+	// Paths in the config are relative to the config file.
+	dir := filepath.Dir(path)
+	cfg.File = path
+	if !filepath.IsAbs(cfg.Root) {
+		cfg.Root = filepath.Join(dir, cfg.Root)
+	}
+	if cfg.Scope != "" && !filepath.IsAbs(cfg.Scope) {
+		cfg.Scope = filepath.Join(dir, cfg.Scope)
+	}
+	for i, rules := range cfg.Rules {
+		if !filepath.IsAbs(rules) {
+			cfg.Rules[i] = filepath.Join(dir, rules)
+		}
+	}
+	for i, output := range cfg.Outputs {
+		if output.Path != "" && output.Path != "-" && !filepath.IsAbs(output.Path) {
+			cfg.Outputs[i].Path = filepath.Join(dir, output.Path)
+		}
+	}
+
+	return cfg, nil
+}
+
+// findConfig returns the path of the first config file found in the working
+// directory or one of its parents, or an empty string.
+func findConfig() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		for _, name := range configFileNames {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// options returns the analyzer options of the config.
+func (c *Config) options() analyzer.Options {
+	return analyzer.Options{
+		Jobs:              c.Jobs,
+		Root:              c.Root,
+		Include:           c.Include,
+		Exclude:           c.Exclude,
+		NoDefaultExcludes: c.NoDefaultExcludes,
+		NoGitignore:       c.NoGitignore,
+	}
+}
+
+// allIssues returns the built-in issues and the issues of the config's rule
+// files. Rules colliding with other issues are an error.
+func (c *Config) allIssues() ([]analyzer.Issue, error) {
+	all := analyzer.AllIssues()
+	for _, path := range c.Rules {
+		rules, err := analyzer.LoadRules(path)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, rules...)
+	}
+
+	if _, err := analyzer.Compile(all); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// issues returns the issues selected by the config, with severity overrides
+// applied.
+func (c *Config) issues() ([]analyzer.Issue, error) {
+	all, err := c.allIssues()
+	if err != nil {
+		return nil, err
+	}
+
+	for id, name := range c.Severity {
+		found := false
+		for i := range all {
+			if strings.EqualFold(all[i].Identifier, id) {
+				severity, err := analyzer.ParseSeverity(name)
+				if err != nil {
+					return nil, fmt.Errorf("issue %s: %s", id, err)
+				}
+				all[i].Severity = severity
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown issue %q in config", id)
+		}
+	}
+
+	severities := []analyzer.Severity{}
+	for _, name := range c.Severities {
+		severity, err := analyzer.ParseSeverity(name)
+		if err != nil {
+			return nil, err
+		}
+		severities = append(severities, severity)
+	}
+
+	return analyzer.SelectIssues(all, analyzer.Selection{
+		Only:       c.Enable,
+		Exclude:    c.Disable,
+		Severities: severities,
+	})
+}
+
+// print writes the config as YAML to stdout.
+func (c *Config) print() error {
+	content, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if c.File != "" {
+		fmt.Printf("# %s\n", c.File)
+	}
+	fmt.Print(string(content))
+	return nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/byterocket/c4udit/analyzer"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "src", "lib")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := `
+include: [src/]
+disable: [N-02]
+severity: {G-06: nc}
+outputs:
+  - {format: markdown, path: report.md}
+  - {format: text}
+report: {title: Audit, project: Example}
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, ".c4udit.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The config is discovered from a subdirectory.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(sub); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.File != filepath.Join(dir, ".c4udit.yaml") || cfg.Root != dir {
+		t.Errorf("unexpected config location: file %q, root %q", cfg.File, cfg.Root)
+	}
+	if cfg.Outputs[0].Path != filepath.Join(dir, "report.md") || cfg.Outputs[1].Path != "" {
+		t.Errorf("unexpected outputs: %v", cfg.Outputs)
+	}
+	if cfg.Report.Title != "Audit" || cfg.Report.Project != "Example" {
+		t.Errorf("unexpected report metadata: %v", cfg.Report)
+	}
+
+	issues, err := cfg.issues()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != len(analyzer.AllIssues())-1 {
+		t.Errorf("got %d issues, want all but N-02", len(issues))
+	}
+	for _, issue := range issues {
+		if issue.Identifier == "N-02" {
+			t.Errorf("disabled issue N-02 enabled")
+		}
+		if issue.Identifier == "G-06" && issue.Severity != analyzer.NC {
+			t.Errorf("severity override not applied to G-06")
+		}
+	}
+}
+
+func TestLoadConfigPaths(t *testing.T) {
+	dir := t.TempDir()
+	abs := filepath.Join(t.TempDir(), "project")
+
+	tests := []struct {
+		root string
+		want string
+	}{
+		{"", dir},
+		{"src", filepath.Join(dir, "src")},
+		{abs, abs},
+	}
+	for _, test := range tests {
+		path := filepath.Join(dir, "c4udit.json")
+		config := `{"root": "` + filepath.ToSlash(test.root) + `", "scope": "` + filepath.ToSlash(abs) + `/scope.txt"}`
+		if err := ioutil.WriteFile(path, []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := loadConfig(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Clean(cfg.Root) != test.want {
+			t.Errorf("root %q: got %q, want %q", test.root, cfg.Root, test.want)
+		}
+		if filepath.Clean(cfg.Scope) != filepath.Join(abs, "scope.txt") {
+			t.Errorf("root %q: got scope %q", test.root, cfg.Scope)
+		}
+	}
+
+	// An empty YAML config is valid.
+	path := filepath.Join(dir, ".c4udit.yaml")
+	if err := ioutil.WriteFile(path, []byte("# nothing yet\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadConfig(path); err != nil {
+		t.Errorf("empty config: %s", err)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	unknownField := filepath.Join(dir, "unknown.json")
+	if err := ioutil.WriteFile(unknownField, []byte(`{"includes": ["src/"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadConfig(unknownField); err == nil {
+		t.Errorf("expected error for unknown config field")
+	}
+
+	cfg := &Config{Disable: []string{"X-99"}}
+	if _, err := cfg.issues(); err == nil {
+		t.Errorf("expected error for unknown issue")
+	}
+
+	cfg = &Config{Severity: map[string]string{"G-06": "critical"}}
+	if _, err := cfg.issues(); err == nil {
+		t.Errorf("expected error for unknown severity")
+	}
+}
+
+func TestApplyFlagsExcludes(t *testing.T) {
+	defer func() { exclude, excludePath = stringList{}, stringList{} }()
+	// Issues and file globs have their own flags, so that a glob like *
+	// does not disable all issues.
+	for name, value := range map[string]string{"exclude": "N-02", "exclude-path": "*,test/"} {
+		if err := flag.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &Config{}
+	if err := applyFlags(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg.Disable, []string{"N-02"}) {
+		t.Errorf("got disabled issues %v", cfg.Disable)
+	}
+	if !reflect.DeepEqual(cfg.Exclude, []string{"*", "test/"}) {
+		t.Errorf("got excluded files %v", cfg.Exclude)
+	}
+}
@@ -1,3 +1,8 @@
 module github.com/byterocket/c4udit
 
 go 1.17
+
+require (
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
+	gopkg.in/yaml.v3 v3.0.1
+)
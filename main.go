@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strings"
 
 	"github.com/byterocket/c4udit/analyzer"
 )
@@ -14,61 +15,280 @@ func main() {
 	if *help {
 		printHelpAndExit()
 	}
+	if *version {
+		fmt.Println("c4udit", analyzer.Version)
+		return
+	}
+
+	cfg, err := loadConfig(*configFile)
+	if err != nil {
+		printErrorAndExit(err)
+	}
+	if err := applyFlags(cfg); err != nil {
+		printErrorAndExit(err)
+	}
+
+	// Run commands.
+	paths := flag.Args()
+	triagePath := ""
+	switch command(paths, afterDashes()) {
+	case "rules":
+		runRulesCommand(cfg, flag.Args()[1:])
+		return
+	case "config":
+		runConfigCommand(cfg, flag.Args()[1:])
+		return
+	case "render":
+		runRenderCommand(cfg, flag.Args()[1:])
+		return
+	case "toc":
+		runTocCommand(flag.Args()[1:])
+		return
+	case "import-triage":
+		// Analyze the files and drop the findings triaged as false
+		// positives.
+		if len(paths) < 3 {
+			printHelpAndExit()
+		}
+		triagePath, paths = paths[1], paths[2:]
+	}
 
 	// Expect at least one user argument.
-	if len(flag.Args()) == 0 && !*toc{
+	if len(paths) == 0 {
 		printHelpAndExit()
 	}
 
+	opts := cfg.options()
+	root, err := analyzer.ReportRoot(paths, opts)
+	if err != nil {
+		printErrorAndExit(err)
+	}
+	scopeFile, scope, err := loadScope(cfg, root)
+	if err != nil {
+		printErrorAndExit(err)
+	}
+	opts.Scope = scope
+	for _, file := range analyzer.MissingScopeFiles(scope) {
+		fmt.Fprintf(os.Stderr, "c4udit warning: %s: listed in scope of %s but not found\n", displayPath(file), scopeFile)
+	}
+
+	// List the files to analyze without analyzing them.
+	if *listFiles {
+		files, errs, err := analyzer.ListFiles(paths, opts)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		for _, file := range files {
+			fmt.Println(file)
+		}
+		if scope != nil && len(files) == 0 {
+			warnNothingInScope(scopeFile)
+		}
+		for _, e := range errs {
+			fmt.Fprintln(os.Stderr, "c4udit warning:", e.Error())
+		}
+		return
+	}
+
+	issues, err := cfg.issues()
+	if err != nil {
+		printErrorAndExit(err)
+	}
+	if err := checkOutputs(cfg.Outputs); err != nil {
+		printErrorAndExit(err)
+	}
+	if *toc && !hasMarkdown(cfg.Outputs) {
+		fmt.Fprintln(os.Stderr, "c4udit warning: -t only applies to Markdown reports, use -format markdown")
+	}
+
 	// Run analyzer.
 	report, err := analyzer.Run(
-		analyzer.AllIssues(),
-		flag.Args(),
+		issues,
+		paths,
+		opts,
 	)
 	if err != nil {
 		printErrorAndExit(err)
 	}
+	report.Metadata = cfg.Report
+	report.Scope = scopeFile
+	if scope != nil && len(report.FilesAnalyzed) == 0 {
+		warnNothingInScope(scopeFile)
+	}
 
-	if *saveToFile {
-		// Save report in markdown format to file.
-		err = ioutil.WriteFile(
-			"c4udit-report.md",
-			[]byte(report.Markdown(false)),
-			0777,
-		)
+	// Compare with the baseline, after recording a new one.
+	if *baselineWrite != "" {
+		err = analyzer.NewBaseline(report).Save(*baselineWrite)
 		if err != nil {
 			printErrorAndExit(err)
 		}
-	} else if *toc {
-		// Save report in markdown format to file.
-		// fmt.Println(flag.Args()[0])
-		// str, err1 := analyzer.ToC_Convertor(flag.Args()[0])
-		str, err1 := analyzer.ToC_Convertor("c4udit-report.md")
-		if err1 != nil {
-			printErrorAndExit(err1)
+	}
+	if *baseline != "" {
+		b, err := analyzer.LoadBaseline(*baseline)
+		if err != nil {
+			printErrorAndExit(err)
 		}
-		err = ioutil.WriteFile(
-			"c4udit-report-toc.md",
-			[]byte(str),
-			0777,
-		)
+		report.ApplyBaseline(b)
+	}
+	if triagePath != "" {
+		t, err := analyzer.LoadTriage(triagePath)
 		if err != nil {
 			printErrorAndExit(err)
 		}
+		dropped, unmatched := report.ApplyTriage(t)
+		fmt.Fprintf(os.Stderr, "c4udit: dropped %d findings triaged as false positives\n", dropped)
+		for _, row := range unmatched {
+			fmt.Fprintf(os.Stderr, "c4udit warning: %s:%d: %s finding in %s no longer found\n", triagePath, row.Line, row.Issue, row.File)
+		}
+	}
+
+	renderOpts := renderOptions(report)
+
+	// Write report to the configured outputs.
+	err = writeOutputs(report, cfg.Outputs, renderOpts)
+	if err != nil {
+		printErrorAndExit(err)
+	}
+
+	// Print paths that could not be analyzed.
+	for _, e := range report.Errors {
+		fmt.Fprintln(os.Stderr, "c4udit warning:", e.Error())
+	}
+	if *unusedSuppressions {
+		for _, s := range report.UnusedSuppressions() {
+			fmt.Fprintf(os.Stderr, "c4udit warning: %s:%d: unused suppression `%s`\n", s.File, s.Line, s.Directive)
+		}
+	}
+	if cfg.Strict && len(report.Errors) > 0 {
+		os.Exit(1)
+	}
+}
 
-	} else {
-		// Print report to stdout.
-		fmt.Println(report.String())
+// applyFlags overrides `cfg` with the flags set on the command line.
+func applyFlags(cfg *Config) error {
+	outputSet := false
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "j":
+			cfg.Jobs = *jobs
+		case "root":
+			cfg.Root = *root
+		case "strict":
+			cfg.Strict = *strict
+		case "rules":
+			cfg.Rules = append(cfg.Rules, rules...)
+		case "only":
+			cfg.Enable = only
+		case "include":
+			cfg.Include = include
+		case "exclude":
+			cfg.Disable = exclude
+		case "exclude-path":
+			cfg.Exclude = excludePath
+		case "no-default-excludes":
+			cfg.NoDefaultExcludes = *noDefaultExcludes
+		case "no-gitignore":
+			cfg.NoGitignore = *noGitignore
+		case "scope":
+			cfg.Scope = *scope
+		case "no-scope":
+			cfg.NoScope = *noScope
+		case "severity":
+			cfg.Severities = severities
+		case "format", "o", "s":
+			outputSet = true
+		}
+	})
 
+	// Output flags replace the outputs of the config.
+	if outputSet {
+		output := Output{Format: *format, Path: *outputPath}
+		if *saveToFile {
+			if output.Format == "" && output.Path == "" {
+				// The separate reports submitted to Code4rena.
+				cfg.Outputs = []Output{
+					{Format: "qa", Path: "qa-report.md"},
+					{Format: "gas", Path: "gas-report.md"},
+				}
+				return nil
+			}
+			if output.Format == "" {
+				output.Format = "markdown"
+			}
+			if output.Path == "" {
+				output.Path = "c4udit-report.md"
+			}
+		}
+		if output.Format == "" {
+			output.Format = "text"
+		}
+		cfg.Outputs = []Output{output}
+	}
+
+	if len(cfg.Outputs) == 0 {
+		cfg.Outputs = []Output{{Format: "text"}}
 	}
+	return nil
+}
+
+func init() {
+	flag.Var(&rules, "rules", "Load additional issues from a rule file. Can be repeated.")
+	flag.Var(&only, "only", "Only report the comma-separated issues, e.g. G-01,L-*.")
+	flag.Var(&exclude, "exclude", "Do not report the comma-separated issues, e.g. N-02.")
+	flag.Var(&excludePath, "exclude-path", "Do not analyze the files matching the comma-separated globs, e.g. test/.")
+	flag.Var(&include, "include", "Only analyze the files matching the comma-separated globs, e.g. src/.")
+	flag.Var(&severities, "severity", "Only report issues of the comma-separated severities: gas, nc, low.")
+}
+
+// stringList is a flag that can be given several times or as a
+// comma-separated list.
+type stringList []string
 
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(s string) error {
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			*l = append(*l, e)
+		}
+	}
+	return nil
 }
 
 // Flags
 var (
 	help       = flag.Bool("h", false, "Print help text.")
-	saveToFile = flag.Bool("s", false, "Save report as file.")
-	toc        = flag.Bool("t", false, "Save Report as file with Toc")
+	version    = flag.Bool("version", false, "Print the version.")
+	format     = flag.String("format", "", "Output format: text, markdown, qa, gas, json, sarif, csv, html, github, checkstyle or junit.")
+	outputPath = flag.String("o", "", "Write the report to a file instead of stdout.")
+	configFile = flag.String("config", "", "Config file (default: .c4udit.json or .c4udit.yaml in the working directory or a parent).")
+	saveToFile = flag.Bool("s", false, "Save the QA and gas reports to qa-report.md and gas-report.md.")
+	toc        = flag.Bool("t", false, "Add a table of contents to Markdown reports (-format markdown).")
+	jobs       = flag.Int("j", 0, "Number of files to analyze in parallel.")
+	strict     = flag.Bool("strict", false, "Exit with an error if any path could not be analyzed.")
+	root       = flag.String("root", "", "Project root that paths in the report are relative to.")
+	carets     = flag.Bool("carets", false, "Underline the matched code of each finding.")
+	rules      = stringList{}
+	only       = stringList{}
+	exclude    = stringList{}
+	severities = stringList{}
+	include    = stringList{}
+
+	excludePath       = stringList{}
+	noDefaultExcludes = flag.Bool("no-default-excludes", false, "Analyze dependencies, tests, mocks and scripts too.")
+	noGitignore       = flag.Bool("no-gitignore", false, "Analyze files ignored by .gitignore files too.")
+	listFiles         = flag.Bool("list-files", false, "List the files to analyze without analyzing them.")
+	scope             = flag.String("scope", "", "Only analyze the files listed in the scope file, e.g. scope.txt.")
+	noScope           = flag.Bool("no-scope", false, "Do not read the scope table of the contest README.md.")
+
+	unusedSuppressions = flag.Bool("unused-suppressions", false, "List suppression comments that suppress nothing.")
+	baseline           = flag.String("baseline", "", "Only report findings not in the baseline file.")
+	baselineWrite      = flag.String("baseline-write", "", "Write all findings to a baseline file.")
+
+	repoURL = flag.String("repo-url", "", "Repository the findings link to (default: the origin remote).")
+	commit  = flag.String("commit", "", "Commit the findings link to (default: HEAD).")
 )
 
 const helpText = `c4udit is a static analyzer for solidity contracts based on regexs.
@@ -81,19 +301,244 @@ Note that c4udit has a high rate of false positives. Check the results carefully
 
 Usage:
 	c4udit [flags] [files...]
+	c4udit rules validate [rule files...]
+	c4udit config print
+	c4udit [flags] render report.json
+	c4udit toc report.md
+	c4udit [flags] import-triage triage.csv [files...]
 
 Flags:
 	-h    Print help text.
-	-s    Save report as file.
-	-t    Save report as file with Toc ex: ./c4udit -t
+	-version
+	      Print the version of c4udit.
+	-format FORMAT
+	      Output format: text (default), markdown, qa, gas, json, sarif,
+	      csv, html, github, checkstyle or junit. qa and gas are the
+	      Code4rena QA and gas reports. See docs/report.schema.json for the
+	      JSON format. SARIF 2.1.0 logs can be uploaded to GitHub code
+	      scanning. CSV has one row per finding, with empty status and
+	      notes columns for triage. HTML is a single file that works
+	      offline. github prints GitHub Actions annotations, relative to
+	      $GITHUB_WORKSPACE if set. checkstyle and junit are XML reports
+	      for CI servers like Jenkins and GitLab.
+	-o FILE
+	      Write the report to FILE instead of stdout.
+	-config FILE
+	      Read the config from FILE (default: .c4udit.json, .c4udit.yaml
+	      or .c4udit.yml in the working directory or a parent).
+	-s    Save the Code4rena QA report (low and non-critical findings) to
+	      qa-report.md and the gas report to gas-report.md. With -format
+	      or -o, save the report as file (default: markdown to
+	      c4udit-report.md).
+	-t    Add a table of contents to Markdown reports, see -format
+	      markdown.
+	-j N  Analyze N files in parallel (default: number of CPUs).
+	-strict
+	      Exit with status 1 if any path could not be analyzed.
+	-root DIR
+	      Report paths relative to DIR (default: common root of files).
+	-carets
+	      Underline the matched code of each finding with carets.
+	-repo-url URL
+	      Link the findings of Markdown reports to the repository at URL,
+	      e.g. https://github.com/org/repo (default: the origin remote of
+	      the git repository).
+	-commit COMMIT
+	      Link the findings to COMMIT (default: HEAD of the git
+	      repository).
+	-only IDS
+	      Only report the comma-separated issues. Wildcards select all
+	      matching issues, e.g. -only G-*,L-04.
+	-exclude IDS
+	      Do not report the comma-separated issues, e.g. -exclude N-02.
+	-severity SEVERITIES
+	      Only report issues of the comma-separated severities: gas, nc
+	      and low.
+	-include GLOBS
+	      Only analyze the files matching the comma-separated globs,
+	      e.g. -include 'src/**/*.sol'.
+	-exclude-path GLOBS
+	      Do not analyze the files matching the comma-separated globs,
+	      e.g. -exclude-path 'src/legacy/,*Mock*.sol'.
+	-no-default-excludes
+	      Also analyze the lib/, test/, script/ and similar directories
+	      of the project root, node_modules/ and mocks/ directories, and
+	      *.t.sol and *.s.sol files.
+	-no-gitignore
+	      Also analyze files ignored by .gitignore files.
+	-list-files
+	      Print the files that would be analyzed and exit.
+	-scope FILE
+	      Only analyze the files listed in FILE, e.g. scope.txt with one
+	      file per line, or a README.md with a scope table. Without it,
+	      the scope table of the README.md in the analyzed root or its
+	      parents in the git repository is used, if any.
+	-no-scope
+	      Do not read the scope table of the contest README.md.
+	-rules FILE
+	      Load additional issues from the rule FILE, in addition to the
+	      rule files of the config. Can be repeated.
+	-unused-suppressions
+	      List c4udit-disable comments that no longer suppress anything.
+	-baseline FILE
+	      Only report findings that are not in the baseline FILE.
+	-baseline-write FILE
+	      Record all findings in the baseline FILE.
+
+Selecting files:
+	Files in the given directories are analyzed unless they, or one of
+	their parent directories, match an exclude glob or are ignored by a
+	.gitignore file. Globs without a slash match file and directory
+	names, others match paths relative to the root. Files given as
+	arguments are always analyzed.
+	With a scope, only the listed files in the given paths are analyzed
+	and the report names the scope file. Listed files that do not exist
+	are reported as warnings, which do not fail -strict.
+
+Suppressing findings:
+	// c4udit-disable-next-line G-06,N-02
+	// c4udit-disable-line
+	// c4udit-disable G-06
+	// c4udit-enable G-06
+	Without issue identifiers, all issues are suppressed.
+
+Config file (.c4udit.yaml):
+	root: .
+	include: [src/]
+	exclude: [src/legacy/, "*Mock*.sol"]
+	noDefaultExcludes: false
+	noGitignore: false
+	scope: scope.txt
+	rules: [rules/team.yaml]
+	enable: []
+	disable: [N-02]
+	severities: [gas, low]
+	severity: {G-06: nc}
+	outputs:
+	  - {format: text}
+	  - {format: markdown, path: c4udit-report.md}
+	report: {title: "Audit Report", project: Example, author: me}
+	Flags override the config file.
+
+Rule file (YAML, or JSON if named *.json):
+	rules:
+	  - id: X-01
+	    severity: low              # gas, nc or low
+	    title: Use of tx.origin
+	    impact: ...
+	    pattern: \btx\.origin\b
+	    recommendation: ...
+	    scope: code                # code, comments or strings
+	    excludePatterns: ['tx\.origin == msg\.sender']
+	    files: [src/]
+
+Commands:
+	rules validate    Check that all rules, including the given rule files,
+	                  compile and have unique identifiers.
+	config print      Print the effective config, after applying flags.
+	render            Render a JSON report in the output format, e.g.
+	                  c4udit -format markdown render report.json.
+	toc               Add or refresh the table of contents of a Markdown
+	                  report in place, e.g. after editing it by hand.
+	import-triage     Analyze the files and drop the findings whose status
+	                  is "false positive" or "fp" in a CSV report, e.g.
+	                  c4udit -s import-triage triage.csv src/.
+
+	To analyze a directory named like a command, e.g. rules, write ./rules
+	or separate the paths from the flags with --, e.g. c4udit -- rules.
 
 `
 
+// commands are the words that run a command when given as first argument.
+var commands = map[string]bool{
+	"rules":         true,
+	"config":        true,
+	"render":        true,
+	"toc":           true,
+	"import-triage": true,
+}
+
+// command returns the command given as first of the arguments `args`, or an
+// empty string if they are paths to analyze. Command names are always run
+// as command, unless `pathsOnly` is set because the arguments follow `--`.
+func command(args []string, pathsOnly bool) string {
+	if pathsOnly || len(args) == 0 || !commands[args[0]] {
+		return ""
+	}
+	return args[0]
+}
+
+// afterDashes reports whether the arguments were separated from the flags
+// by `--`.
+func afterDashes() bool {
+	i := len(os.Args) - flag.NArg() - 1
+	return i >= 1 && os.Args[i] == "--"
+}
+
+// warnNothingInScope warns that none of the files to analyze is listed in
+// `scopeFile`.
+func warnNothingInScope(scopeFile string) {
+	fmt.Fprintf(os.Stderr, "c4udit warning: none of the given files is listed in scope of %s, use -no-scope to analyze them\n", scopeFile)
+}
+
 func printHelpAndExit() {
 	fmt.Print(helpText)
 	os.Exit(0)
 }
 
+func runRulesCommand(cfg *Config, args []string) {
+	if len(args) == 0 || args[0] != "validate" {
+		printHelpAndExit()
+	}
+
+	cfg.Rules = append(cfg.Rules, args[1:]...)
+	issues, err := cfg.allIssues()
+	if err != nil {
+		printErrorAndExit(err)
+	}
+	fmt.Printf("%d rules OK\n", len(issues))
+}
+
+func runConfigCommand(cfg *Config, args []string) {
+	if len(args) != 1 || args[0] != "print" {
+		printHelpAndExit()
+	}
+
+	if err := cfg.print(); err != nil {
+		printErrorAndExit(err)
+	}
+}
+
+func runRenderCommand(cfg *Config, args []string) {
+	if len(args) != 1 {
+		printHelpAndExit()
+	}
+
+	report, err := analyzer.LoadReport(args[0])
+	if err != nil {
+		printErrorAndExit(err)
+	}
+	err = writeOutputs(report, cfg.Outputs, renderOptions(report))
+	if err != nil {
+		printErrorAndExit(err)
+	}
+}
+
+func runTocCommand(args []string) {
+	if len(args) != 1 {
+		printHelpAndExit()
+	}
+
+	content, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		printErrorAndExit(err)
+	}
+	err = ioutil.WriteFile(args[0], []byte(analyzer.AddTableOfContents(string(content))), 0644)
+	if err != nil {
+		printErrorAndExit(err)
+	}
+}
+
 func printErrorAndExit(err error) {
 	fmt.Println("c4checker Error:")
 	fmt.Print(err.Error())
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	// Directories named like commands, e.g. for rules: [rules/team.yaml].
+	for _, name := range []string{"rules", "config"} {
+		if err := os.Mkdir(name, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		args      []string
+		pathsOnly bool
+		want      string
+	}{
+		{[]string{}, false, ""},
+		{[]string{"src"}, false, ""},
+		{[]string{"toc", "report.md"}, false, "toc"},
+		// Commands run next to a directory of the same name.
+		{[]string{"rules", "validate", "rules/team.yaml"}, false, "rules"},
+		{[]string{"config", "print"}, false, "config"},
+		// The directories are analyzed as ./rules or after --.
+		{[]string{"./rules"}, false, ""},
+		{[]string{"rules"}, true, ""},
+	}
+	for _, tt := range tests {
+		if got := command(tt.args, tt.pathsOnly); got != tt.want {
+			t.Errorf("command(%q, %v): got %q, want %q", tt.args, tt.pathsOnly, got, tt.want)
+		}
+	}
+}
+
+func TestLoadScope(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{".git", "src"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	readme := "# Contest\n\n## Scope\n\n| Contract |\n| --- |\n| src/A.sol |\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte(readme), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The README.md is found from the analyzed root, not the working
+	// directory.
+	scopeFile, files, err := loadScope(&Config{}, filepath.Join(dir, "src"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "src", "A.sol")}
+	if scopeFile != filepath.Join(dir, "README.md") || !reflect.DeepEqual(files, want) {
+		t.Errorf("got scope %q with files %v, want README.md with %v", scopeFile, files, want)
+	}
+
+	scopeFile, files, err = loadScope(&Config{NoScope: true}, dir)
+	if err != nil || scopeFile != "" || files != nil {
+		t.Errorf("-no-scope: got scope %q with files %v and error %v", scopeFile, files, err)
+	}
+}
+
+func TestHasMarkdown(t *testing.T) {
+	tests := []struct {
+		outputs []Output
+		want    bool
+	}{
+		{[]Output{{Format: "text"}}, false},
+		{[]Output{{Format: "qa", Path: "qa-report.md"}, {Format: "gas", Path: "gas-report.md"}}, false},
+		{[]Output{{Format: "json", Path: "report.json"}, {Format: "markdown"}}, true},
+	}
+	for _, test := range tests {
+		if got := hasMarkdown(test.outputs); got != test.want {
+			t.Errorf("hasMarkdown(%v) = %v, want %v", test.outputs, got, test.want)
+		}
+	}
+}
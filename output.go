@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/byterocket/c4udit/analyzer"
+)
+
+// renderers render a Report in an output format.
+var renderers = map[string]func(r *analyzer.Report, opts analyzer.RenderOptions) (string, error){
+	"text": func(r *analyzer.Report, opts analyzer.RenderOptions) (string, error) {
+		return r.Text(opts) + "\n", nil
+	},
+	"markdown": func(r *analyzer.Report, opts analyzer.RenderOptions) (string, error) {
+		return r.Markdown(opts), nil
+	},
+	"qa": func(r *analyzer.Report, opts analyzer.RenderOptions) (string, error) {
+		return r.QAMarkdown(opts) + "\n", nil
+	},
+	"gas": func(r *analyzer.Report, opts analyzer.RenderOptions) (string, error) {
+		return r.GasMarkdown(opts) + "\n", nil
+	},
+	"json": func(r *analyzer.Report, opts analyzer.RenderOptions) (string, error) {
+		content, err := r.JSON()
+		return string(content) + "\n", err
+	},
+	"sarif": func(r *analyzer.Report, opts analyzer.RenderOptions) (string, error) {
+		content, err := r.SARIF()
+		return string(content) + "\n", err
+	},
+	"csv": func(r *analyzer.Report, opts analyzer.RenderOptions) (string, error) {
+		content, err := r.CSV()
+		return string(content), err
+	},
+	"html": func(r *analyzer.Report, opts analyzer.RenderOptions) (string, error) {
+		content, err := r.HTML()
+		return string(content), err
+	},
+	"github": func(r *analyzer.Report, opts analyzer.RenderOptions) (string, error) {
+		return r.GitHub(opts), nil
+	},
+	"checkstyle": func(r *analyzer.Report, opts analyzer.RenderOptions) (string, error) {
+		content, err := r.Checkstyle()
+		return string(content) + "\n", err
+	},
+	"junit": func(r *analyzer.Report, opts analyzer.RenderOptions) (string, error) {
+		content, err := r.JUnit()
+		return string(content) + "\n", err
+	},
+}
+
+// renderOptions returns the options for rendering report `r`, set by flags
+// and the environment.
+func renderOptions(r *analyzer.Report) analyzer.RenderOptions {
+	return analyzer.RenderOptions{
+		ToC:        *toc,
+		Carets:     *carets,
+		Workspace:  os.Getenv("GITHUB_WORKSPACE"),
+		Repository: repository(r),
+	}
+}
+
+// repository returns the repository the findings of report `r` link to: the
+// git repository containing the report's root, with the origin remote and
+// HEAD overridden by -repo-url and -commit. It returns nil if the URL or
+// the commit is unknown.
+func repository(r *analyzer.Report) *analyzer.Repository {
+	repo, err := analyzer.DetectRepository(r.Root)
+	if err != nil {
+		repo = analyzer.Repository{}
+	}
+	if *repoURL != "" {
+		repo.URL = analyzer.NormalizeRepoURL(*repoURL)
+	}
+	if *commit != "" {
+		repo.Commit = *commit
+	}
+
+	if repo.URL == "" || repo.Commit == "" {
+		if *repoURL != "" || *commit != "" {
+			fmt.Fprintln(os.Stderr, "c4udit warning: findings are not linked, the repository URL or commit is unknown")
+		}
+		return nil
+	}
+	return &repo
+}
+
+// formats returns the names of all output formats.
+func formats() []string {
+	names := []string{}
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkOutputs returns an error if any of `outputs` has an unknown format.
+func checkOutputs(outputs []Output) error {
+	for _, output := range outputs {
+		if _, ok := renderers[output.Format]; !ok {
+			return fmt.Errorf("unknown output format %q, expected one of %v", output.Format, formats())
+		}
+	}
+	return nil
+}
+
+// hasMarkdown reports whether any of `outputs` is a Markdown report, the
+// only format with a table of contents.
+func hasMarkdown(outputs []Output) bool {
+	for _, output := range outputs {
+		if output.Format == "markdown" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeOutputs renders report `r` to each of `outputs`.
+func writeOutputs(r *analyzer.Report, outputs []Output, opts analyzer.RenderOptions) error {
+	if err := checkOutputs(outputs); err != nil {
+		return err
+	}
+
+	for _, output := range outputs {
+		content, err := renderers[output.Format](r, opts)
+		if err != nil {
+			return err
+		}
+
+		if output.Path == "" || output.Path == "-" {
+			fmt.Print(content)
+			continue
+		}
+		err = ioutil.WriteFile(output.Path, []byte(content), 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
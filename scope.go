@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/byterocket/c4udit/analyzer"
+)
+
+// loadScope returns the scope file and the files in scope: the files of the
+// config's scope file or, without one, of the scope table in the README.md
+// of the analyzed project at `root`. Nil files mean all files are in scope.
+func loadScope(cfg *Config, root string) (string, []string, error) {
+	if cfg.NoScope {
+		return "", nil, nil
+	}
+	if cfg.Scope != "" {
+		files, err := analyzer.LoadScope(cfg.Scope)
+		return displayPath(cfg.Scope), files, err
+	}
+
+	readme, err := findReadme(root)
+	if err != nil || readme == "" {
+		return "", nil, err
+	}
+	files, err := analyzer.LoadScope(readme)
+	if err != nil || len(files) == 0 {
+		return "", nil, err
+	}
+	readme = displayPath(readme)
+	fmt.Fprintf(os.Stderr, "c4udit: only analyzing the %d files listed in the scope table of %s, use -no-scope to analyze all files\n", len(files), readme)
+	return readme, files, nil
+}
+
+// displayPath returns `path` relative to the working directory, if it is
+// in it.
+func displayPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
+
+// findReadme returns the path of the README.md in `dir`, or in one of its
+// parents within its git repository.
+// It returns an empty string if there is none.
+func findReadme(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	inRepository := inGitRepository(dir)
+	for current := dir; ; current = filepath.Dir(current) {
+		readme := filepath.Join(current, "README.md")
+		if _, err := os.Stat(readme); err == nil {
+			return readme, nil
+		}
+
+		// Do not leave the repository, or the directory outside of one.
+		if !inRepository || filepath.Dir(current) == current {
+			return "", nil
+		}
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			return "", nil
+		}
+	}
+}
+
+// inGitRepository reports whether `dir` is in a git repository.
+func inGitRepository(dir string) bool {
+	for current := dir; ; current = filepath.Dir(current) {
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			return true
+		}
+		if filepath.Dir(current) == current {
+			return false
+		}
+	}
+}